@@ -0,0 +1,232 @@
+package evendeep
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/flags/cms"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// copyAccessors copies the properties which are exposed by methods
+// after the struct fields copied, if WithAccessors is enabled.
+//
+// A source property is an exported field, or a getter, `GetName()` or
+// `Name()`, which returns a value, or a value and an error. A target
+// property is an exported field, or a setter `SetName(v)` which
+// returns nothing or an error. The getters and setters are matched by
+// name and the values are converted through the converters as the
+// fields. A field to field copy is skipped here if the target field
+// has been set by the struct iterator, that is, in cms.ByName mode,
+// or in the ordinal mode if the source has a field at its position;
+// so is a getter to field copy in cms.ByName mode, see
+// forEachTargetField. In the ordinal mode the fields of the different
+// layouts, such as a protobuf message with the leading unexported
+// states, are matched by name here.
+//
+// The values are copied with the children of params, so its flags and
+// the tags of the enclosing field are kept.
+func (c *cpController) copyAccessors(params *Params, from, to reflect.Value) (err error) {
+	src, dst := accessorsOf(from, false), accessorsOf(to, true)
+	if !src.IsValid() || !dst.IsValid() {
+		return
+	}
+
+	// the struct iterator is done, its last field isn't the owner of
+	// the properties.
+	defer func(accessor accessor) { params.accessor = accessor }(params.accessor)
+	params.accessor = nil
+
+	ec := errors.New("accessors copy errors (%v -> %v)", src.Type(), dst.Type())
+	defer ec.Defer(&err)
+
+	byName := c.targetOriented || params.isGroupedFlagOKDeeply(cms.ByName)
+	for _, name := range targetProperties(dst) {
+		if params.shouldBeIgnored(name) {
+			continue
+		}
+
+		sf, srcField := exportedField(src.Elem(), name)
+		df, dstField := exportedField(dst.Elem(), name)
+		if dstField && (byName || srcField && hasFieldAt(src.Type().Elem(), df.Index)) {
+			continue // set by the struct iterator
+		}
+
+		var val reflect.Value
+		var source string
+		if srcField {
+			if _, ignored := params.parseFieldTags(sf.Tag); ignored {
+				continue
+			}
+			val, source = src.Elem().FieldByIndex(sf.Index), name
+		} else if getter, gn := getterOf(src, name); getter.IsValid() {
+			var e error
+			if val, e = callGetter(getter); e != nil {
+				ec.Attach(e)
+				continue
+			}
+			source = gn + "()"
+		} else {
+			continue
+		}
+
+		c.tracer.add(params, name, source, "accessor", TraceCopied, "")
+		dbglog.Log("    accessor %q: %v -> %v", name, source, ref.Typfmt(dst.Type()))
+		if dstField {
+			fv := dst.Elem().FieldByIndex(df.Index)
+			child := fieldParams(c, params, df.Tag)
+			e := c.copyTo(child, val, fv)
+			child.revoke()
+			ec.Attach(e)
+			c.tracer.fail(e)
+			continue
+		}
+		e := c.callSetter(params, dst.MethodByName("Set"+name), val)
+		ec.Attach(e)
+		c.tracer.fail(e)
+	}
+	return
+}
+
+// getTargetFieldByGetter reads the property name by a getter for the
+// target field pointed by params.accessor in cms.ByName mode, the
+// value is converted to the field type. getterName is empty if no
+// such getter.
+func (c *cpController) getTargetFieldByGetter(params *Params, src reflect.Value, name string) (val reflect.Value, getterName string, err error) { //nolint:lll
+	var getter reflect.Value
+	if getter, getterName = getterOf(src, name); !getter.IsValid() {
+		return
+	}
+	if val, err = callGetter(getter); err != nil {
+		return
+	}
+	if ft := params.accessor.FieldType(); ft != nil && !val.Type().AssignableTo(*ft) {
+		var tag reflect.StructTag
+		if sf := params.accessor.StructField(); sf != nil {
+			tag = sf.Tag
+		}
+		nv := reflect.New(*ft).Elem()
+		child := fieldParams(c, params, tag)
+		err, val = c.copyTo(child, val, nv), nv
+		child.revoke()
+	}
+	return
+}
+
+// accessorsOf returns the pointer to the struct value v, so the
+// methods of both value and pointer receivers can be called. A source
+// struct which isn't addressable is copied.
+func accessorsOf(v reflect.Value, target bool) (ptr reflect.Value) {
+	v = ref.Rindirect(v)
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	switch {
+	case v.CanAddr():
+		ptr = v.Addr()
+	case !target:
+		ptr = reflect.New(v.Type())
+		ptr.Elem().Set(v)
+	}
+	return
+}
+
+// targetProperties returns the names of the exported fields and the
+// setters of a struct pointer.
+func targetProperties(dst reflect.Value) (names []string) {
+	seen := make(map[string]bool)
+	for _, sf := range reflect.VisibleFields(dst.Type().Elem()) {
+		if sf.IsExported() && !sf.Anonymous && !seen[sf.Name] {
+			seen[sf.Name] = true
+			names = append(names, sf.Name)
+		}
+	}
+	typ := dst.Type()
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		if name, ok := strings.CutPrefix(m.Name, "Set"); ok && name != "" && !seen[name] && isSetter(m.Type) {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return
+}
+
+func exportedField(v reflect.Value, name string) (sf reflect.StructField, ok bool) {
+	if sf, ok = v.Type().FieldByName(name); ok {
+		ok = sf.IsExported() && !sf.Anonymous
+	}
+	return
+}
+
+// hasFieldAt tests if the struct typ has a field at the position
+// index, so the ordinal struct iterator has copied it to the target
+// field at the same position.
+func hasFieldAt(typ reflect.Type, index []int) bool {
+	for _, i := range index {
+		if typ = ref.RindirectType(typ); typ.Kind() != reflect.Struct || i >= typ.NumField() {
+			return false
+		}
+		typ = typ.Field(i).Type
+	}
+	return true
+}
+
+// isSetter tests if a method type (with the receiver) is like
+// `SetName(v)` or `SetName(v) error`.
+func isSetter(mt reflect.Type) bool {
+	n, withErr := funcResults(mt)
+	return mt.NumIn() == 2 && !mt.IsVariadic() && n == 0 && (withErr || mt.NumOut() == 0)
+}
+
+// getterOf returns the getter `GetName()` or `Name()` of the property
+// name of a struct pointer.
+func getterOf(src reflect.Value, name string) (getter reflect.Value, getterName string) {
+	for _, gn := range []string{"Get" + name, name} {
+		if m := src.MethodByName(gn); m.IsValid() {
+			if n, _ := funcResults(m.Type()); m.Type().NumIn() == 0 && n == 1 {
+				return m, gn
+			}
+		}
+	}
+	return
+}
+
+// callGetter calls a getter and returns its value, or the error it
+// returned.
+func callGetter(getter reflect.Value) (val reflect.Value, err error) {
+	out := getter.Call(nil)
+	if len(out) > 1 && !out[1].IsNil() {
+		err, _ = out[1].Interface().(error) //nolint:errcheck //it's an error
+		return
+	}
+	return out[0], nil
+}
+
+// callSetter converts val to the parameter type of a setter and calls
+// it.
+func (c *cpController) callSetter(params *Params, setter, val reflect.Value) (err error) {
+	arg := reflect.New(setter.Type().In(0)).Elem()
+	child := fieldParams(c, params, "")
+	err = c.copyTo(child, val, arg)
+	child.revoke()
+	if err != nil {
+		return
+	}
+	if out := setter.Call([]reflect.Value{arg}); len(out) > 0 && !out[0].IsNil() {
+		err, _ = out[0].Interface().(error) //nolint:errcheck //it's an error
+	}
+	return
+}
+
+// fieldParams makes a child Params of owner for copying a value into
+// the field which is tagged with tag, or into a setter if tag is empty.
+// owner can be nil.
+func fieldParams(c *cpController, owner *Params, tag reflect.StructTag) (params *Params) {
+	params = newParams(withOwnersSimple(c, owner))
+	params.fieldTag = tag
+	return
+}
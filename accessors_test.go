@@ -0,0 +1,144 @@
+package evendeep_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+// userMessage is like a protobuf-generated message.
+type userMessage struct {
+	state int //nolint:unused //like protoimpl.MessageState
+
+	Id    int64
+	Name  string
+	Email string
+}
+
+func (x *userMessage) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *userMessage) GetDisplayName() string { return strings.ToUpper(x.Name) }
+
+func (x *userMessage) Age() (int32, error) { return 42, nil }
+
+// user is a domain struct with the setters.
+type user struct {
+	ID          string
+	DisplayName string
+	Age         int
+
+	email string
+}
+
+var errBadEmail = errors.New("bad email")
+
+func (u *user) SetEmail(s string) error {
+	if !strings.Contains(s, "@") {
+		return errBadEmail
+	}
+	u.email = s
+	return nil
+}
+
+func (u *user) SetId(id int) { u.ID = fmt.Sprint("u-", id) }
+
+func TestAccessors(t *testing.T) {
+	src := &userMessage{Id: 7, Name: "alice", Email: "alice@example.com"}
+	for _, opts := range [][]evendeep.Opt{
+		{evendeep.WithAccessorsOpt},
+		{evendeep.WithAccessorsOpt, evendeep.WithStrategies(cms.ByName)},
+	} {
+		var u user
+		if err := evendeep.New().CopyTo(src, &u, opts...); err != nil {
+			t.Fatal(err)
+		}
+		want := user{ID: "u-7", DisplayName: "ALICE", Age: 42, email: "alice@example.com"}
+		if u != want {
+			t.Fatalf("bad result:\n got %+v\nwant %+v", u, want)
+		}
+	}
+
+	// a setter returns an error
+	var u user
+	err := evendeep.New().CopyTo(userMessage{Email: "nobody"}, &u, evendeep.WithAccessorsOpt)
+	if !errors.Is(err, errBadEmail) {
+		t.Fatalf("want errBadEmail, got %v", err)
+	}
+
+	// disabled by default
+	u = user{}
+	if err := evendeep.New().CopyTo(src, &u); err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasPrefix(u.ID, "u-") || u.Age != 0 {
+		t.Fatalf("the accessors shouldn't be used: %+v", u)
+	}
+}
+
+// ageMessage is like a protobuf-generated message whose leading fields
+// are the unexported states.
+type ageMessage struct {
+	state     int //nolint:unused //like protoimpl.MessageState
+	sizeCache int //nolint:unused //like protoimpl.SizeCache
+
+	Name string
+	Age  int32
+}
+
+func TestAccessors_differentLayouts(t *testing.T) {
+	type person struct {
+		Name string
+		Age  int
+	}
+	var msg ageMessage
+	if err := evendeep.New().CopyTo(&person{Name: "bob", Age: 3}, &msg, evendeep.WithAccessorsOpt); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Name != "bob" || msg.Age != 3 {
+		t.Fatalf("bad result: %+v", msg)
+	}
+}
+
+func TestAccessors_ordinal(t *testing.T) {
+	type ab struct{ A, B string }
+	type ba struct{ B, A string }
+	var tgt ba
+	if err := evendeep.New().CopyTo(ab{A: "a", B: "b"}, &tgt, evendeep.WithAccessorsOpt); err != nil {
+		t.Fatal(err)
+	}
+	if want := (ba{B: "a", A: "b"}); tgt != want {
+		t.Fatalf("the fields set by position are overwritten:\n got %+v\nwant %+v", tgt, want)
+	}
+}
+
+type levelMessage struct{ Name string }
+
+func (x *levelMessage) GetLevel() int { return len(x.Name) * 100 }
+
+type level struct {
+	Name string
+	n    int8
+}
+
+func (l *level) SetLevel(n int8) { l.n = n }
+
+func TestAccessors_fieldTags(t *testing.T) {
+	src := struct{ L []levelMessage }{[]levelMessage{{"warn"}}}
+	var tgt struct {
+		L []level `copy:",strict"`
+	}
+	err := evendeep.New().CopyTo(&src, &tgt, evendeep.WithAccessorsOpt)
+	var ce *evendeep.ConversionError
+	if !errors.As(err, &ce) {
+		t.Fatalf("want a ConversionError by the strict tag, got %v (%+v)", err, tgt.L)
+	}
+}
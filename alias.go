@@ -0,0 +1,84 @@
+package evendeep
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// aliasKey identifies a source reference: a pointer, a map or a
+// slice (with its length).
+type aliasKey struct {
+	ptr unsafe.Pointer
+	typ reflect.Type
+	len int
+}
+
+func newAliasKey(from reflect.Value) (key aliasKey, ok bool) {
+	switch from.Kind() { //nolint:exhaustive //others have no identity
+	case reflect.Ptr, reflect.Map:
+		if ok = !from.IsNil(); ok {
+			key = aliasKey{ptr: from.UnsafePointer(), typ: from.Type()}
+		}
+	case reflect.Slice:
+		if ok = !from.IsNil() && from.Len() > 0; ok {
+			key = aliasKey{ptr: from.UnsafePointer(), typ: from.Type(), len: from.Len()}
+		}
+	}
+	return
+}
+
+// aliasTable returns the table shared by the whole copying, it's
+// stored in the root Params.
+func (params *Params) aliasTable() map[aliasKey]reflect.Value {
+	root := params
+	for root.owner != nil {
+		root = root.owner
+	}
+	if root.aliases == nil {
+		root.aliases = make(map[aliasKey]reflect.Value)
+	}
+	return root.aliases
+}
+
+// lookupAlias returns the target object which has been made for the
+// source reference from, if it can be assigned to targetType.
+func (params *Params) lookupAlias(from reflect.Value, targetType reflect.Type) (dst reflect.Value, ok bool) {
+	if params == nil || params.controller == nil {
+		return
+	}
+	var key aliasKey
+	if key, ok = newAliasKey(from); ok {
+		if dst, ok = params.aliasTable()[key]; ok {
+			ok = dst.IsValid() && dst.CanInterface() && dst.Type().AssignableTo(targetType)
+		}
+	}
+	return
+}
+
+// registerAlias records that dst is the target object made for the
+// source reference from. The returned function should be deferred.
+//
+// A reference being copied is always registered so that a cycle
+// can be closed. After the copying completed, the record is kept
+// only if the controller preserves aliasing, else the next
+// reference to the same source object will be duplicated.
+func (params *Params) registerAlias(from, dst reflect.Value) (done func()) {
+	done = func() {}
+	if params == nil || params.controller == nil {
+		return
+	}
+	key, ok := newAliasKey(from)
+	if !ok || !dst.IsValid() {
+		return
+	}
+
+	table := params.aliasTable()
+	if _, exists := table[key]; exists {
+		return
+	}
+	table[key] = dst
+	if !params.controller.preserveAliasing {
+		done = func() { delete(table, key) }
+	}
+	return
+}
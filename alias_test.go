@@ -0,0 +1,114 @@
+package evendeep_test
+
+import (
+	"testing"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+type aliasNode struct {
+	Name   string
+	Parent *aliasNode
+	Kids   []*aliasNode
+	A, B   *aliasNode
+	Attrs  map[string]any
+}
+
+func newAliasTree() (root, shared, kid *aliasNode) {
+	attrs := map[string]any{"x": 1}
+	shared = &aliasNode{Name: "shared", Attrs: attrs}
+	root = &aliasNode{Name: "root", A: shared, B: shared, Attrs: attrs}
+	kid = &aliasNode{Name: "kid", Parent: root}
+	root.Kids = []*aliasNode{kid}
+	return
+}
+
+func TestMakeCloneWithAliasing(t *testing.T) {
+	root, shared, kid := newAliasTree()
+
+	c := evendeep.MakeClone(root).(aliasNode)
+	if c.A != c.B || c.A == shared || c.A.Name != "shared" {
+		t.Fatalf("shared pointer should be mapped to one clone: A=%p, B=%p, orig=%p", c.A, c.B, shared)
+	}
+	if c.Kids[0] == kid || c.Kids[0].Parent == root {
+		t.Fatalf("the kid should be cloned")
+	}
+	if c.Kids[0].Parent.Kids[0] != c.Kids[0] {
+		t.Fatalf("the back-pointer of kid should point to the cloned parent")
+	}
+	c.Attrs["y"] = 2
+	if c.A.Attrs["y"] != 2 || len(root.Attrs) != 1 {
+		t.Fatalf("shared map should be mapped to one clone: %v, orig: %v", c.A.Attrs, root.Attrs)
+	}
+}
+
+func TestWithPreserveAliasing(t *testing.T) {
+	root, shared, kid := newAliasTree()
+
+	for _, preserve := range []bool{true, false} {
+		var tgt aliasNode
+		err := evendeep.New(evendeep.WithPreserveAliasing(preserve)).CopyTo(root, &tgt)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if preserve && tgt.Kids[0].Parent != &tgt {
+			t.Fatalf("preserve=%v: the cycle should be closed at the target root", preserve)
+		}
+		if !preserve && tgt.Kids[0] != kid {
+			t.Fatalf("preserve=%v: the pointer elements should be shared as the plain copying", preserve)
+		}
+		if tgt.A == shared || tgt.B == shared || tgt.A.Name != "shared" || tgt.B.Name != "shared" {
+			t.Fatalf("preserve=%v: bad copy: A=%+v, B=%+v", preserve, tgt.A, tgt.B)
+		}
+		if (tgt.A == tgt.B) != preserve {
+			t.Fatalf("preserve=%v: A=%p, B=%p", preserve, tgt.A, tgt.B)
+		}
+	}
+}
+
+func TestSlicePointerElemsShared(t *testing.T) {
+	type holder struct{ P []*int }
+	x := 1
+	var tgt holder
+	if err := evendeep.New().CopyTo(holder{P: []*int{&x}}, &tgt); err != nil || tgt.P[0] != &x {
+		t.Fatalf("the pointer elements should be shared by New().CopyTo: %v", err)
+	}
+	tgt = holder{}
+	if evendeep.DeepCopy(holder{P: []*int{&x}}, &tgt); tgt.P[0] != &x {
+		t.Fatal("the pointer elements should be shared by DeepCopy")
+	}
+	if c := evendeep.MakeClone(holder{P: []*int{&x}}).(holder); c.P[0] == &x || *c.P[0] != 1 {
+		t.Fatal("the pointer elements should be cloned by MakeClone")
+	}
+}
+
+func TestMakeCloneWithSharedSlices(t *testing.T) {
+	type pair struct{ A, B []int }
+	x := []int{1, 2}
+	c := evendeep.MakeClone(pair{A: x, B: x}).(pair)
+	if &c.A[0] != &c.B[0] || &c.A[0] == &x[0] || c.A[1] != 2 {
+		t.Fatalf("the shared slice should be mapped to one clone: %v, %v", c.A, c.B)
+	}
+
+	var tgt pair
+	if err := evendeep.New(evendeep.WithPreserveAliasing(false), evendeep.WithStrategies(cms.SliceCopy)).CopyTo(pair{A: x, B: x}, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if &tgt.A[0] == &tgt.B[0] || &tgt.A[0] == &x[0] {
+		t.Fatalf("the shared slice should be duplicated without aliasing: %v, %v", tgt.A, tgt.B)
+	}
+}
+
+func TestMakeCloneWithSelfReferencingSlice(t *testing.T) {
+	ss := make([]any, 2)
+	ss[0], ss[1] = ss, "x"
+	c, ok := evendeep.MakeClone(ss).([]any)
+	if !ok || len(c) != 2 || c[1] != "x" {
+		t.Fatalf("bad clone: %T", c)
+	}
+	inner, ok := c[0].([]any)
+	if !ok || &inner[0] != &c[0] || &c[0] == &ss[0] {
+		t.Fatal("the self-reference should point to the clone")
+	}
+}
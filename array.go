@@ -0,0 +1,144 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// Length policies for WithLengthPolicy and the `len=` tag. They decide
+// how to copy an array or slice of n elements into an array, or an
+// array into a slice, whose length is L:
+//
+//	policy     n > L                       n < L
+//	truncate   drop the extra elements     keep the rest of target
+//	pad        drop the extra elements     zero the rest of target
+//	strict     ErrLengthMismatch           ErrLengthMismatch
+//	resize     a slice grows to n          a slice shrinks to n
+//
+// LenTruncate is the default for an array copied into an array, so
+// the rest of target is kept as before, and LenPad is the default for
+// a slice copied into an array. resize works as pad for an array
+// target since an array can't be resized. LenResize is the default
+// for an array copied into a slice, that is, the slice is an exact
+// copy of the array, the slice strategies such as cms.SliceMerge are
+// not applied.
+const (
+	LenTruncate = "truncate"
+	LenPad      = "pad"
+	LenStrict   = "strict"
+	LenResize   = "resize"
+)
+
+// lengthPolicy returns the length policy by the `len=` tag of the
+// nearest target field, or by WithLengthPolicy. It reports
+// ErrUnknownName for an unknown policy.
+func (params *Params) lengthPolicy() (policy string, err error) {
+	if params == nil {
+		return
+	}
+	if tags := params.nearestFieldTags(); tags != nil && tags.lengthPolicy != "" {
+		policy = tags.lengthPolicy
+	} else if params.controller != nil {
+		policy = params.controller.lengthPolicy
+	}
+	switch policy {
+	case "", LenTruncate, LenPad, LenStrict, LenResize:
+	default:
+		err = ErrUnknownName.FormatWith("length policy", policy)
+	}
+	return
+}
+
+// copyToArray copies the elements of an array or slice into the
+// target array by the length policy, the elements are converted if
+// their types are different, such as a []byte to [16]byte.
+func (c *cpController) copyToArray(params *Params, src, tgt reflect.Value) (err error) {
+	if !tgt.CanSet() {
+		return ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&src), ref.Typfmtv(&src))
+	}
+
+	policy, err := params.lengthPolicy()
+	if err != nil {
+		return
+	}
+	if policy == "" {
+		policy = LenPad
+		if src.Kind() == reflect.Array {
+			policy = LenTruncate
+		}
+	}
+	n, l := src.Len(), tgt.Len()
+	dbglog.Log("    copy %d elements to array of %d, length policy: %q", n, l, policy)
+	if policy == LenStrict && n != l {
+		return ErrLengthMismatch.FormatWith(ref.Valfmt(&src), n, ref.Typfmtv(&tgt), l)
+	}
+
+	ec := errors.New("array copy errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ec.Defer(&err)
+
+	for i := 0; i < n && i < l; i++ {
+		ec.Attach(c.copyElem(params, src.Index(i), tgt.Index(i)))
+	}
+	if policy != LenTruncate {
+		for i := n; i < l; i++ {
+			tgt.Index(i).Set(reflect.Zero(tgt.Type().Elem()))
+		}
+	}
+	return
+}
+
+// copyArrayToSlice copies an array into the target slice by the length
+// policy.
+func (c *cpController) copyArrayToSlice(params *Params, src, tgt, tgtptr reflect.Value) (err error) {
+	policy, err := params.lengthPolicy()
+	if err != nil {
+		return
+	}
+	n, l := src.Len(), tgt.Len()
+	dbglog.Log("    copy array of %d to slice of %d, length policy: %q", n, l, policy)
+	if policy == LenStrict && n != l {
+		return ErrLengthMismatch.FormatWith(ref.Valfmt(&src), n, ref.Typfmtv(&tgt), l)
+	}
+
+	ec := errors.New("array copy errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ec.Defer(&err)
+
+	size := l
+	if policy == LenResize || policy == "" {
+		size = n
+	}
+	ns := reflect.MakeSlice(tgt.Type(), size, size)
+	if policy == LenTruncate {
+		reflect.Copy(ns, tgt)
+	}
+	for i := 0; i < n && i < size; i++ {
+		ec.Attach(c.copyElem(params, src.Index(i), ns.Index(i)))
+	}
+
+	if tgtptr.Kind() == reflect.Ptr {
+		tgtptr.Elem().Set(ns)
+	} else if tgtptr.CanSet() {
+		tgtptr.Set(ns)
+	}
+	if params != nil {
+		params.resultForNewSlice = &ns
+	}
+	return
+}
+
+// copyElem copies an element of array or slice into the addressable
+// target element, the pointer is cloned, and the different types are
+// converted.
+func (c *cpController) copyElem(params *Params, se, te reflect.Value) (err error) {
+	if se.Type().AssignableTo(te.Type()) {
+		if se, err = cloneSliceElem(c, params, se); err == nil {
+			te.Set(se)
+		}
+		return
+	}
+	return c.copyTo(newParams(withOwners(c, params, &se, &te, &se, &te)), se, te)
+}
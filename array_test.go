@@ -0,0 +1,117 @@
+package evendeep_test
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestArrayAndSlice(t *testing.T) {
+	// slice to array, zero-padded by default
+	a3 := [3]int{7, 8, 9}
+	if err := evendeep.New().CopyTo([]int{1}, &a3); err != nil || a3 != [3]int{1, 0, 0} {
+		t.Fatalf("bad result: %v, %v", a3, err)
+	}
+	if err := evendeep.New().CopyTo([]int{1, 2, 3, 4}, &a3); err != nil || a3 != [3]int{1, 2, 3} {
+		t.Fatalf("bad result: %v, %v", a3, err)
+	}
+
+	// array to slice, an exact copy by default
+	s := []int64{5, 5, 5, 5, 5}
+	if err := evendeep.New().CopyTo([3]int{1, 2, 3}, &s); err != nil || !reflect.DeepEqual(s, []int64{1, 2, 3}) {
+		t.Fatalf("bad result: %v, %v", s, err)
+	}
+
+	// array to array, the elements are converted
+	var a2 [2]string
+	if err := evendeep.New().CopyTo([3]int{1, 2, 3}, &a2); err != nil || a2 != [2]string{"1", "2"} {
+		t.Fatalf("bad result: %v, %v", a2, err)
+	}
+
+	// ip and uuid
+	var ip net.IP
+	if err := evendeep.New().CopyTo([4]byte{10, 0, 0, 1}, &ip); err != nil || ip.String() != "10.0.0.1" {
+		t.Fatalf("bad result: %v, %v", ip, err)
+	}
+	var a4 [4]byte
+	if err := evendeep.New().CopyTo(net.IPv4(10, 0, 0, 2).To4(), &a4); err != nil || a4 != [4]byte{10, 0, 0, 2} {
+		t.Fatalf("bad result: %v, %v", a4, err)
+	}
+	var uuid [16]byte
+	if err := evendeep.New().CopyTo([]byte("0123456789abcdef"), &uuid); err != nil || string(uuid[:]) != "0123456789abcdef" {
+		t.Fatalf("bad result: %v, %v", uuid, err)
+	}
+	var b []byte
+	if err := evendeep.New().CopyTo(uuid, &b); err != nil || string(b) != "0123456789abcdef" {
+		t.Fatalf("bad result: %v, %v", b, err)
+	}
+}
+
+func TestWithLengthPolicy(t *testing.T) {
+	cases := []struct {
+		policy string
+		src    any
+		tgt    any
+		want   any
+	}{
+		{evendeep.LenTruncate, []int{1}, [3]int{7, 8, 9}, [3]int{1, 8, 9}},
+		{evendeep.LenPad, []int{1}, [3]int{7, 8, 9}, [3]int{1, 0, 0}},
+		{evendeep.LenResize, []int{1}, [3]int{7, 8, 9}, [3]int{1, 0, 0}},
+		{evendeep.LenStrict, []int{1, 2, 3}, [3]int{7, 8, 9}, [3]int{1, 2, 3}},
+		{evendeep.LenTruncate, [2]int{1, 2}, []int{7, 8, 9}, []int{1, 2, 9}},
+		{evendeep.LenTruncate, [2]int{1, 2}, []int{7}, []int{1}},
+		{evendeep.LenPad, [2]int{1, 2}, []int{7, 8, 9}, []int{1, 2, 0}},
+		{evendeep.LenResize, [2]int{1, 2}, []int{7, 8, 9}, []int{1, 2}},
+	}
+	for i, tc := range cases {
+		tgt := reflect.New(reflect.TypeOf(tc.tgt))
+		tgt.Elem().Set(reflect.ValueOf(tc.tgt))
+		if err := evendeep.New().CopyTo(tc.src, tgt.Interface(), evendeep.WithLengthPolicy(tc.policy)); err != nil {
+			t.Fatalf("%d. %v: %v", i, tc.policy, err)
+		}
+		if got := tgt.Elem().Interface(); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%d. %v: bad result: %v, want %v", i, tc.policy, got, tc.want)
+		}
+	}
+
+	// the defaults
+	a3 := [3]int{7, 8, 9}
+	if err := evendeep.New().CopyTo([2]int{1, 2}, &a3); err != nil || a3 != [3]int{1, 2, 9} {
+		t.Fatalf("array to array: bad result: %v, %v", a3, err)
+	}
+	a3 = [3]int{7, 8, 9}
+	if err := evendeep.New().CopyTo([]int{1, 2}, &a3); err != nil || a3 != [3]int{1, 2, 0} {
+		t.Fatalf("slice to array: bad result: %v, %v", a3, err)
+	}
+
+	// an unknown policy
+	if err := evendeep.New(evendeep.WithLengthPolicy("truncated")).CopyTo([2]int{1, 2}, &a3); !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v", err)
+	}
+	var bad struct {
+		A [3]int `copy:",len=pading"`
+	}
+	if err := evendeep.New().CopyTo(struct{ A []int }{[]int{1}}, &bad); !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v", err)
+	}
+
+	// by tag
+	type packet struct {
+		Header [4]byte `copy:",len=strict"`
+		Body   []byte
+	}
+	var p packet
+	err := evendeep.New().CopyTo(struct {
+		Header []byte
+		Body   [2]byte
+	}{[]byte{1, 2, 3}, [2]byte{4, 5}}, &p)
+	if !errors.Is(err, evendeep.ErrLengthMismatch) {
+		t.Fatalf("want ErrLengthMismatch, got %v", err)
+	}
+	if !reflect.DeepEqual(p.Body, []byte{4, 5}) {
+		t.Fatalf("bad result: %v", p)
+	}
+}
@@ -0,0 +1,126 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// Channel strategy names for WithChanStrategy and the `chan=` tag.
+const (
+	ChanShare    = "share"    // the target shares the source channel, the default
+	ChanFresh    = "fresh"    // the target is a new empty channel of the same capacity
+	ChanSnapshot = "snapshot" // the target is a new channel with a copy of the buffered elements
+)
+
+// chanStrategy returns the channel strategy by the `chan=` tag of the
+// nearest target field, or by WithChanStrategy. It reports
+// ErrUnknownName for an unknown strategy.
+func (params *Params) chanStrategy() (strategy string, err error) {
+	strategy = ChanShare
+	if params == nil {
+		return
+	}
+	if tags := params.nearestFieldTags(); tags != nil && tags.chanStrategy != "" {
+		strategy = tags.chanStrategy
+	} else if params.controller != nil && params.controller.chanStrategy != "" {
+		strategy = params.controller.chanStrategy
+	}
+	switch strategy {
+	case ChanShare, ChanFresh, ChanSnapshot:
+	default:
+		err = ErrUnknownName.FormatWith("channel strategy", strategy)
+	}
+	return
+}
+
+// makeChan makes a channel of typ, which might be a directional
+// channel type.
+func makeChan(typ reflect.Type, capacity int) reflect.Value {
+	ch := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, typ.Elem()), capacity)
+	return ch.Convert(typ)
+}
+
+// snapshotChan makes a new channel of typ, and copies the buffered
+// elements of the source channel into it, they are converted by the
+// converters if the element types are different.
+//
+// The source channel is drained and refilled without blocking, so it
+// is not atomic if there are other goroutines sending to or receiving
+// from it. If the source cannot be refilled since it has been filled
+// by others, ErrChanRefill is returned with the snapshot, the dropped
+// elements are kept in the snapshot only. A closed source can't be
+// refilled, its elements are moved to the target, and the target is
+// closed too. An unbuffered channel has no elements, its snapshot is
+// an empty channel.
+func (c *cpController) snapshotChan(params *Params, from reflect.Value, typ reflect.Type) (ch reflect.Value, err error) {
+	if from.Type().ChanDir() != reflect.BothDir {
+		err = ErrCannotCopy.FormatWith(ref.Valfmt(&from), ref.Typfmtv(&from), "snapshot", typ)
+		return
+	}
+
+	var items []reflect.Value
+	for n := from.Len(); len(items) < n; {
+		v, ok := from.TryRecv()
+		if !ok {
+			break
+		}
+		items = append(items, v)
+	}
+	closed := false
+	if len(items) == 0 && from.Cap() > 0 {
+		// probe it, so that a closed channel can be copied as closed.
+		// An unbuffered one isn't probed since a value received from
+		// a blocked sender cannot be put back.
+		switch v, ok := from.TryRecv(); {
+		case ok: // just sent by someone
+			items = append(items, v)
+		case v.IsValid():
+			closed = true
+		}
+	}
+	for i, v := range items {
+		var sent bool
+		sent, closed = trySend(from, v)
+		if closed {
+			break
+		}
+		if !sent {
+			err = ErrChanRefill.FormatWith(ref.Typfmtv(&from), len(items)-i, len(items))
+			break
+		}
+	}
+	dbglog.Log("    snapshot chan: %d items, closed: %v", len(items), closed)
+
+	et := typ.Elem()
+	bc := reflect.MakeChan(reflect.ChanOf(reflect.BothDir, et), from.Cap()) // typ might be a receive-only channel
+	for _, v := range items {
+		if !v.Type().AssignableTo(et) {
+			nv := reflect.New(et).Elem()
+			if e := c.copyTo(newParams(withOwners(c, params, &v, &nv, &v, &nv)), v, nv); e != nil {
+				err = e
+				return
+			}
+			v = nv
+		}
+		bc.Send(v) // never blocks, there are cap(from) items at most
+	}
+	if closed {
+		bc.Close()
+	}
+	ch = bc.Convert(typ)
+	return
+}
+
+// trySend sends v to ch without blocking, it returns closed = true if
+// ch has been closed, or sent = false if ch is full.
+func trySend(ch, v reflect.Value) (sent, closed bool) {
+	defer func() {
+		if recover() != nil {
+			sent, closed = false, true
+		}
+	}()
+	sent = ch.TrySend(v)
+	return
+}
@@ -0,0 +1,119 @@
+package evendeep_test
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestWithChanStrategy(t *testing.T) {
+	type pipe struct {
+		Shared   chan int
+		Fresh    chan int `copy:",chan=fresh"`
+		Snapshot chan int `copy:",chan=snapshot"`
+	}
+	newPipe := func() pipe {
+		p := pipe{make(chan int, 4), make(chan int, 4), make(chan int, 4)}
+		for i := 1; i <= 3; i++ {
+			p.Shared <- i
+			p.Fresh <- i
+			p.Snapshot <- i
+		}
+		return p
+	}
+
+	src := newPipe()
+	var tgt pipe
+	if err := evendeep.New().CopyTo(&src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if tgt.Shared != src.Shared {
+		t.Fatal("want a shared channel")
+	}
+	if tgt.Fresh == src.Fresh || tgt.Fresh == nil || cap(tgt.Fresh) != 4 || len(tgt.Fresh) != 0 {
+		t.Fatalf("want a fresh channel, got len %d", len(tgt.Fresh))
+	}
+	if tgt.Snapshot == src.Snapshot || len(tgt.Snapshot) != 3 || len(src.Snapshot) != 3 {
+		t.Fatalf("want a snapshot, got len %d, source len %d", len(tgt.Snapshot), len(src.Snapshot))
+	}
+	for i := 1; i <= 3; i++ {
+		if a, b := <-src.Snapshot, <-tgt.Snapshot; a != i || b != i {
+			t.Fatalf("bad order: %d, %d, want %d", a, b, i)
+		}
+	}
+
+	// by option, the elements are converted, the tags of target fields are used
+	var strs struct{ Shared, Fresh, Snapshot chan string }
+	src = newPipe()
+	close(src.Shared)
+	if err := evendeep.New(evendeep.WithChanStrategy(evendeep.ChanSnapshot)).CopyTo(&src, &strs); err != nil {
+		t.Fatal(err)
+	}
+	if len(strs.Shared) != 3 || len(strs.Fresh) != 3 || len(strs.Snapshot) != 3 || <-strs.Snapshot != "1" {
+		t.Fatalf("bad result: %d, %d, %d", len(strs.Shared), len(strs.Fresh), len(strs.Snapshot))
+	}
+	// a closed channel is moved and closed
+	var got []string
+	for s := range strs.Shared {
+		got = append(got, s)
+	}
+	if len(got) != 3 || len(src.Shared) != 0 {
+		t.Fatalf("bad result: %v", got)
+	}
+}
+
+func TestWithChanStrategy_unbuffered(t *testing.T) {
+	type pipe struct {
+		C chan int `copy:",chan=snapshot"`
+	}
+	src := pipe{make(chan int)}
+	go func() { src.C <- 1 }()
+	time.Sleep(10 * time.Millisecond) // let the sender block
+
+	var tgt pipe
+	if err := evendeep.New().CopyTo(&src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if tgt.C == nil || tgt.C == src.C || cap(tgt.C) != 0 {
+		t.Fatal("want a new unbuffered channel")
+	}
+	select {
+	case v := <-src.C:
+		if v != 1 {
+			t.Fatalf("bad value: %v", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the value of the blocked sender is lost")
+	}
+}
+
+func TestWithChanStrategy_shareConverted(t *testing.T) {
+	src := struct{ C chan int }{make(chan int, 2)}
+	src.C <- 7
+	var tgt struct{ C chan string }
+	if err := evendeep.New().CopyTo(&src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if len(tgt.C) != 1 || <-tgt.C != "7" || len(src.C) != 1 {
+		t.Fatalf("want a converted snapshot, got len %d", len(tgt.C))
+	}
+
+	// a directional channel can't be snapshotted
+	ro := struct{ C <-chan int }{src.C}
+	if err := evendeep.New().CopyTo(&ro, &tgt); !errors.Is(err, evendeep.ErrCannotCopy) {
+		t.Fatalf("want ErrCannotCopy, got %v", err)
+	}
+}
+
+func TestWithChanStrategy_unknown(t *testing.T) {
+	src := struct{ C chan int }{make(chan int)}
+	var tgt struct {
+		C chan int `copy:",chan=snapshots"`
+	}
+	if err := evendeep.New().CopyTo(&src, &tgt); !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v", err)
+	}
+}
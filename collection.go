@@ -0,0 +1,351 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// CollectionAdapter fills a user collection target, such as a set,
+// an ordered map, or a linked list.
+//
+// The adapters are registered by WithCollectionAdapters, and only
+// the registered ones are consulted. MethodCollectionAdapter is a
+// ready-made one which fills a target by its methods.
+//
+// The source can be a slice, an array, a map, an iter.Seq[V] or
+// iter.Seq2[K, V], or an object which has an All() iterator method.
+type CollectionAdapter interface {
+	// Match tests if the adapter can fill the target type, which is
+	// a pointer to the collection object. It returns the key type (nil
+	// for a list-like collection) and the element type.
+	Match(target reflect.Type) (key, elem reflect.Type, ok bool)
+	// Put adds an element into target. The key is invalid for a
+	// list-like collection.
+	Put(target, key, elem reflect.Value) (err error)
+}
+
+// MethodCollectionAdapter is a CollectionAdapter which fills a target
+// by its methods:
+//
+//	Set(key K, value V) / Put(key K, value V)  // map-like
+//	Add(elem V) / Append(elem V) / Push(elem V) // list-like
+//
+// It isn't enabled by default since a plain struct may have such a
+// method, register it by WithCollectionAdapters if needed.
+type MethodCollectionAdapter struct{}
+
+//nolint:gochecknoglobals //i know that
+var (
+	collectionSetterNames = []string{"Set", "Put"}
+	collectionAdderNames  = []string{"Add", "Append", "Push"}
+)
+
+func (MethodCollectionAdapter) method(target reflect.Type) (m reflect.Method, kv, ok bool) {
+	for _, name := range collectionSetterNames {
+		if m, ok = target.MethodByName(name); ok && m.Type.NumIn() == 3 && !m.Type.IsVariadic() {
+			return m, true, true
+		}
+	}
+	for _, name := range collectionAdderNames {
+		if m, ok = target.MethodByName(name); ok && m.Type.NumIn() == 2 {
+			return m, false, true
+		}
+	}
+	return m, false, false
+}
+
+// Match implements CollectionAdapter.
+func (a MethodCollectionAdapter) Match(target reflect.Type) (key, elem reflect.Type, ok bool) {
+	var m reflect.Method
+	var kv bool
+	if m, kv, ok = a.method(target); ok {
+		if kv {
+			key, elem = m.Type.In(1), m.Type.In(2)
+		} else if elem = m.Type.In(1); m.Type.IsVariadic() {
+			elem = elem.Elem()
+		}
+	}
+	return
+}
+
+// Put implements CollectionAdapter.
+func (a MethodCollectionAdapter) Put(target, key, elem reflect.Value) (err error) {
+	m, kv, _ := a.method(target.Type())
+	args := []reflect.Value{target, elem}
+	if kv {
+		args = []reflect.Value{target, key, elem}
+	}
+	for _, out := range m.Func.Call(args) {
+		if e, ok := out.Interface().(error); ok && e != nil {
+			err = e
+		}
+	}
+	return
+}
+
+// seqOf returns the yield-style iterator of v, it's v itself if v is
+// an iter.Seq or iter.Seq2, or the result of its All() method.
+func seqOf(v reflect.Value) (seq reflect.Value, ok bool) {
+	if isSeqType(v.Type()) {
+		return v, !v.IsNil()
+	}
+	m := v.MethodByName("All")
+	if !m.IsValid() && v.Kind() != reflect.Ptr {
+		if !v.CanAddr() {
+			tmp := reflect.New(v.Type()).Elem()
+			tmp.Set(v)
+			v = tmp
+		}
+		m = v.Addr().MethodByName("All")
+	}
+	if m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 && isSeqType(m.Type().Out(0)) {
+		seq = m.Call(nil)[0]
+		ok = !seq.IsNil()
+	}
+	return
+}
+
+// isSeqType tests if typ is an iter.Seq[V] or iter.Seq2[K, V], or a
+// type with the same underlying type.
+func isSeqType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Func || typ.NumIn() != 1 || typ.NumOut() != 0 {
+		return false
+	}
+	y := typ.In(0)
+	return y.Kind() == reflect.Func && (y.NumIn() == 1 || y.NumIn() == 2) &&
+		y.NumOut() == 1 && y.Out(0).Kind() == reflect.Bool
+}
+
+// hasSeqSource tests if a value of typ may be an iterator source, that
+// is, an iter.Seq, iter.Seq2, or a type with All() iterator method.
+// It checks the type only, so it's cheap for the plain values.
+func hasSeqSource(typ reflect.Type) bool {
+	switch typ.Kind() { //nolint:exhaustive //others can't be an iterator
+	case reflect.Func:
+		return isSeqType(typ)
+	case reflect.Struct:
+		typ = reflect.PointerTo(typ)
+	case reflect.Ptr:
+	default:
+		return false
+	}
+	m, ok := typ.MethodByName("All")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1 && isSeqType(m.Type.Out(0))
+}
+
+// collectSeq pulls the elements from an iterator, up to limit ones if
+// limit >= 0, or else all of them. The keys are invalid for an
+// iter.Seq.
+func collectSeq(seq reflect.Value, limit int) (keys, vals reflect.Value) {
+	y := seq.Type().In(0)
+	kv := y.NumIn() == 2
+	if kv {
+		keys = reflect.MakeSlice(reflect.SliceOf(y.In(0)), 0, 0)
+		vals = reflect.MakeSlice(reflect.SliceOf(y.In(1)), 0, 0)
+	} else {
+		vals = reflect.MakeSlice(reflect.SliceOf(y.In(0)), 0, 0)
+	}
+	goOn := []reflect.Value{reflect.ValueOf(true).Convert(y.Out(0))}
+	stop := []reflect.Value{reflect.Zero(y.Out(0))}
+	yield := reflect.MakeFunc(y, func(args []reflect.Value) []reflect.Value {
+		if kv {
+			keys = reflect.Append(keys, args[0])
+			vals = reflect.Append(vals, args[1])
+		} else {
+			vals = reflect.Append(vals, args[0])
+		}
+		if limit >= 0 && vals.Len() >= limit {
+			return stop
+		}
+		return goOn
+	})
+	seq.Call([]reflect.Value{yield})
+	return
+}
+
+// isCollectionType tests if a struct type is a collection, which can
+// be iterated by All(), or be filled by a registered CollectionAdapter. Such a
+// struct field is copied as a whole rather than being expanded.
+func (c *cpController) isCollectionType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || packageisreserved(typ.PkgPath()) {
+		return false
+	}
+	if hasSeqSource(typ) {
+		return true
+	}
+	ptrTyp := reflect.PointerTo(typ)
+	for _, a := range c.collectionAdapters {
+		if _, _, ok := a.Match(ptrTyp); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// findCollectionAdapter returns the adapter which can fill the target.
+func (c *cpController) findCollectionAdapter(to reflect.Value) (adapter CollectionAdapter, key, elem reflect.Type) {
+	typ := ref.RindirectType(to.Type())
+	if typ.Kind() != reflect.Struct || packageisreserved(typ.PkgPath()) {
+		return
+	}
+	ptrTyp := reflect.PointerTo(typ)
+	var ok bool
+	for _, a := range c.collectionAdapters {
+		if key, elem, ok = a.Match(ptrTyp); ok {
+			adapter = a
+			break
+		}
+	}
+	return
+}
+
+// collectionReceiver returns a pointer to the target collection, the
+// nil pointers are allocated.
+func collectionReceiver(to reflect.Value) (recv reflect.Value, ok bool) {
+	typ := ref.RindirectType(to.Type())
+	ptrTyp := reflect.PointerTo(typ)
+	recv = to
+	for recv.Kind() == reflect.Ptr && recv.Type() != ptrTyp {
+		if recv.IsNil() && recv.CanSet() {
+			recv.Set(reflect.New(recv.Type().Elem()))
+		}
+		recv = recv.Elem()
+	}
+	switch {
+	case recv.Kind() == reflect.Ptr && recv.IsNil() && recv.CanSet():
+		recv.Set(reflect.New(typ))
+	case recv.Kind() == reflect.Struct && recv.CanAddr():
+		recv = recv.Addr()
+	}
+	ok = recv.Kind() == reflect.Ptr && !recv.IsNil()
+	return
+}
+
+// copyCollection copies an iterator (iter.Seq, iter.Seq2, or an object
+// with All() method) to a slice, array or map, or copies a slice,
+// array, map or iterator to a user collection by CollectionAdapter.
+//
+// It returns processed = false if neither of them were found, so
+// that the caller can go ahead. Since it's tried for every value, the
+// types are checked at first without touching the values.
+//
+// An iterator is pulled up to the length of an array target (and one
+// more element to find a longer source for `len=strict`), but it's
+// drained to fill a slice, a map or a user collection, so it must be
+// finite for them.
+//
+//nolint:revive,lll //keep it
+func (c *cpController) copyCollection(params *Params, from, to reflect.Value) (processed bool, err error) {
+	for from.Kind() == reflect.Interface && !from.IsNil() {
+		from = from.Elem()
+	}
+	fk := from.Kind()
+	if !to.IsValid() || !from.IsValid() || !from.CanInterface() || !ref.KindIs(fk, reflect.Func, reflect.Struct, reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map) {
+		return
+	}
+	tt := ref.RindirectType(to.Type())
+	if ref.RindirectType(from.Type()) == tt {
+		return // same type, it's not a conversion
+	}
+
+	var adapter CollectionAdapter
+	var keyType, elemType reflect.Type
+	if len(c.collectionAdapters) > 0 {
+		adapter, keyType, elemType = c.findCollectionAdapter(to)
+	}
+	tk := tt.Kind()
+	if adapter == nil && (!ref.KindIs(tk, reflect.Slice, reflect.Array, reflect.Map) || !hasSeqSource(from.Type())) {
+		return // neither an iterator to a container, nor a user collection
+	}
+
+	var keys, vals reflect.Value
+	switch fk { //nolint:exhaustive //others are returned
+	case reflect.Slice, reflect.Array:
+		if adapter == nil {
+			return
+		}
+		vals = from
+	case reflect.Map:
+		if adapter == nil {
+			return
+		}
+		keys = reflect.MakeSlice(reflect.SliceOf(from.Type().Key()), 0, from.Len())
+		vals = reflect.MakeSlice(reflect.SliceOf(from.Type().Elem()), 0, from.Len())
+		for it := from.MapRange(); it.Next(); {
+			keys, vals = reflect.Append(keys, it.Key()), reflect.Append(vals, it.Value())
+		}
+	default:
+		if ref.IsNil(from) {
+			return
+		}
+		seq, ok := seqOf(from)
+		if !ok {
+			return
+		}
+		limit := -1
+		if adapter == nil && tk == reflect.Array {
+			limit = tt.Len() + 1
+		}
+		keys, vals = collectSeq(seq, limit)
+		if adapter == nil {
+			processed, err = true, c.copySeqTo(params, keys, vals, to, tk)
+			return
+		}
+	}
+
+	processed = true
+	recv, ok := collectionReceiver(to)
+	if !ok {
+		err = ErrCannotSet.FormatWith(ref.Valfmt(&from), ref.Typfmtv(&from), ref.Valfmt(&to), ref.Typfmtv(&to))
+		return
+	}
+	dbglog.Log("    filling collection %v by %T", recv.Type(), adapter)
+	ec := errors.New("collection fill errors (%v -> %v)", from.Type(), recv.Type())
+	defer ec.Defer(&err)
+	for i := 0; i < vals.Len(); i++ {
+		var k reflect.Value
+		if keyType != nil {
+			k = reflect.ValueOf(i) // the index of a list is the key
+			if keys.IsValid() {
+				k = keys.Index(i)
+			}
+			nk := reflect.New(keyType).Elem()
+			if e := c.copyTo(params, k, nk); e != nil {
+				ec.Attach(e)
+				continue
+			}
+			k = nk
+		}
+		ev := reflect.New(elemType).Elem()
+		if e := c.copyTo(params, vals.Index(i), ev); e != nil {
+			ec.Attach(e)
+			continue
+		}
+		ec.Attach(adapter.Put(recv, k, ev))
+	}
+	return
+}
+
+// copySeqTo copies the elements pulled from an iterator to a slice,
+// array or map target. The elements of iter.Seq2 are copied as
+// key-value pairs to a map, or as values to a slice.
+func (c *cpController) copySeqTo(params *Params, keys, vals, to reflect.Value, tk reflect.Kind) (err error) {
+	if tk != reflect.Map {
+		err = c.copyTo(params, vals, to)
+		return
+	}
+	if !keys.IsValid() {
+		err = ErrCannotCopy.FormatWith("iter.Seq", vals.Type(), "", to.Type())
+		return
+	}
+	m := reflect.MakeMapWithSize(reflect.MapOf(keys.Type().Elem(), vals.Type().Elem()), vals.Len())
+	for i := 0; i < vals.Len(); i++ {
+		m.SetMapIndex(keys.Index(i), vals.Index(i))
+	}
+	err = c.copyTo(params, m, to)
+	return
+}
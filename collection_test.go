@@ -0,0 +1,208 @@
+package evendeep_test
+
+import (
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+type collSet struct{ m map[string]bool }
+
+func (s *collSet) Add(v string) {
+	if s.m == nil {
+		s.m = make(map[string]bool)
+	}
+	s.m[v] = true
+}
+
+type collOrderedMap struct {
+	keys []string
+	vals map[string]int
+}
+
+func (m *collOrderedMap) Set(k string, v int) {
+	if m.vals == nil {
+		m.vals = make(map[string]int)
+	}
+	if _, ok := m.vals[k]; !ok {
+		m.keys = append(m.keys, k)
+	}
+	m.vals[k] = v
+}
+
+func (m *collOrderedMap) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for _, k := range m.keys {
+			if !yield(k, m.vals[k]) {
+				return
+			}
+		}
+	}
+}
+
+func TestCopyFromSeq(t *testing.T) {
+	var ints []int
+	if err := evendeep.New().CopyTo(slices.Values([]int{3, 1, 2}), &ints); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(ints, []int{3, 1, 2}) {
+		t.Fatalf("bad: %v", ints)
+	}
+
+	var strs []string
+	if err := evendeep.New().CopyTo(slices.All([]int{7, 8}), &strs); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(strs, []string{"7", "8"}) {
+		t.Fatalf("bad: %v", strs)
+	}
+
+	var m map[string]int
+	if err := evendeep.New().CopyTo(maps.All(map[string]int{"a": 1, "b": 2}), &m); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(m, map[string]int{"a": 1, "b": 2}) {
+		t.Fatalf("bad: %v", m)
+	}
+
+	om := &collOrderedMap{}
+	om.Set("z", 26)
+	om.Set("a", 1)
+	var m2 map[string]int
+	if err := evendeep.New().CopyTo(om, &m2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(m2, map[string]int{"z": 26, "a": 1}) {
+		t.Fatalf("bad: %v", m2)
+	}
+}
+
+func TestCopyFromSeq_unbounded(t *testing.T) {
+	naturals := func(yield func(int) bool) {
+		for i := 1; yield(i); i++ {
+		}
+	}
+
+	var arr [3]int
+	if err := evendeep.New().CopyTo(iter.Seq[int](naturals), &arr); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if arr != [3]int{1, 2, 3} {
+		t.Fatalf("bad: %v", arr)
+	}
+
+	var tgt struct {
+		A [3]int `copy:",len=strict"`
+	}
+	src := struct{ A iter.Seq[int] }{naturals}
+	if err := evendeep.New().CopyTo(src, &tgt); err == nil {
+		t.Fatalf("want the length mismatch error, got %v", tgt.A)
+	}
+}
+
+func TestCollectionAdapter(t *testing.T) {
+	type Src struct {
+		Tags  []string
+		Attrs map[string]int
+	}
+	type Tgt struct {
+		Tags  *collSet
+		Attrs collOrderedMap
+	}
+
+	methods := evendeep.WithCollectionAdapters(evendeep.MethodCollectionAdapter{})
+	var tgt Tgt
+	src := Src{Tags: []string{"x", "y", "x"}, Attrs: map[string]int{"k": 1}}
+	if err := evendeep.New(methods).CopyTo(&src, &tgt); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if tgt.Tags == nil || len(tgt.Tags.m) != 2 || !tgt.Tags.m["x"] || !tgt.Tags.m["y"] {
+		t.Fatalf("bad Tags: %+v", tgt.Tags)
+	}
+	if !reflect.DeepEqual(tgt.Attrs.keys, []string{"k"}) || tgt.Attrs.vals["k"] != 1 {
+		t.Fatalf("bad Attrs: %+v", tgt.Attrs)
+	}
+
+	// and back, the collection is iterated by its All() method
+	var back struct{ Attrs map[string]int }
+	if err := evendeep.New().CopyTo(&struct{ Attrs collOrderedMap }{tgt.Attrs}, &back); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(back.Attrs, map[string]int{"k": 1}) {
+		t.Fatalf("bad back: %+v", back)
+	}
+
+	// iterator source into a user collection
+	var set collSet
+	if err := evendeep.New(methods).CopyTo(slices.Values([]int{1, 2}), &set); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	keys := slices.Collect(maps.Keys(set.m))
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"1", "2"}) {
+		t.Fatalf("bad set: %v", keys)
+	}
+}
+
+type collStack struct{ items []int }
+
+type collStackAdapter struct{}
+
+func (collStackAdapter) Match(target reflect.Type) (key, elem reflect.Type, ok bool) {
+	return nil, reflect.TypeOf(0), target == reflect.TypeOf((*collStack)(nil))
+}
+
+func (collStackAdapter) Put(target, key, elem reflect.Value) (err error) {
+	s := target.Interface().(*collStack)
+	s.items = append([]int{int(elem.Int())}, s.items...)
+	return
+}
+
+func TestWithCollectionAdapters(t *testing.T) {
+	var s collStack
+	err := evendeep.New(evendeep.WithCollectionAdapters(collStackAdapter{})).CopyTo([]int{1, 2, 3}, &s)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if !reflect.DeepEqual(s.items, []int{3, 2, 1}) {
+		t.Fatalf("bad: %v", s.items)
+	}
+}
+
+type collSettable struct {
+	Name  string
+	extra map[string]any
+}
+
+func (s *collSettable) Set(key string, val any) {
+	if s.extra == nil {
+		s.extra = make(map[string]any)
+	}
+	s.extra[key] = val
+}
+
+func TestCollectionAdapterNotImplicit(t *testing.T) {
+	// a struct with a Set method is a plain struct unless
+	// MethodCollectionAdapter is registered
+	var cfg collSettable
+	if err := evendeep.New().CopyTo(map[string]any{"Name": "x"}, &cfg); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if cfg.Name != "x" || cfg.extra != nil {
+		t.Fatalf("bad: %+v", cfg)
+	}
+
+	var tgt struct{ Cfg collSettable }
+	src := struct{ Cfg struct{ Name string } }{struct{ Name string }{"y"}}
+	if err := evendeep.New().CopyTo(&src, &tgt); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if tgt.Cfg.Name != "y" || tgt.Cfg.extra != nil {
+		t.Fatalf("bad: %+v", tgt.Cfg)
+	}
+}
@@ -2,6 +2,7 @@ package evendeep
 
 import (
 	"reflect"
+	"time"
 	"unsafe"
 
 	"gopkg.in/hedzr/errors.v3"
@@ -18,6 +19,8 @@ type cpController struct {
 	copyUnexportedFields       bool
 	copyFunctionResultToTarget bool
 	passSourceAsFunctionInArgs bool
+	funcAdapters               bool // wrap a function of the different type, see WithFuncAdapters
+	accessors                  bool // copy via the getters and setters, see WithAccessors
 	autoExpandStruct           bool // navigate into nested struct?
 	autoNewStruct              bool // create new instance if field is a ptr
 	tryApplyConverterAtFirst   bool // ValueConverters first, or ValueCopiers?
@@ -31,12 +34,42 @@ type cpController struct {
 
 	advanceTargetFieldPointerEvenIfSourceIgnored bool
 
+	preserveAliasing bool // map the shared references 1:1 in target, or duplicate them
+
 	tagKeyName string // struct tag name for cmd.CopyMergeStrategy, default is "" and assumes using "copy" as key name
 
+	numberFormat string // the name of NumberFormat for parsing string to number, see WithNumberFormat
+
+	timeLayouts  []string       // the layouts for parsing string to time, see WithTimeLayouts
+	timeFormat   string         // the layout for formatting time to string, see WithTimeFormat
+	timeLocation *time.Location // the location of naive times, see WithTimeLocation
+	epochUnit    time.Duration  // the unit of integer epoch, see WithEpochUnit
+
+	durationFormat string // the format of time.Duration to string, see WithDurationFormat
+
+	chanStrategy string                                 // share, fresh or snapshot a channel, see WithChanStrategy
+	lockReporter func(typ reflect.Type, paths []string) // report the source type which holds locks, see WithLockReport
+
+	keyTransform MapKeyTransform // rename the keys in copying a map, see WithMapKeyTransform
+	keyCollision string          // the policy for the colliding map keys, see WithKeyCollision
+
+	mapMergePolicy   string           // deep, override or keep the existing map values, see WithMapMergePolicy
+	mapMergeResolver MapMergeResolver // resolve the existing map values, see WithMapMergeResolver
+
+	sliceDedupKey func(elem any) any // the key of slice elements for cms.SliceDedup, see WithSliceDedupKey
+	lengthPolicy  string             // truncate, pad, strict or resize between arrays and slices, see WithLengthPolicy
+
+	shareBytesAbove int // share the byte slices not smaller than it, see WithShareBytesAbove
+
+	collectionAdapters []CollectionAdapter
+	stdConverters      bool // the std types are converted as a whole, see WithStdConverters
+	zeroAsNull         bool // a zero value is converted to an invalid sql.Null*, see WithZeroAsNull
+
 	valueConverters ValueConverters
 	valueCopiers    ValueCopiers
 
 	sourceExtractor SourceValueExtractor // simple struct field value extractor in single depth
+	pathExtractor   SourcePathExtractor  // struct field value extractor at every nesting level
 	targetSetter    TargetValueSetter    //
 
 	// targetOriented indicates both sourceExtractor and target object are available.
@@ -47,12 +80,16 @@ type cpController struct {
 	// or prefer one after name transformed.
 	// See also Name Conversions.
 	targetOriented bool // loop for target struct fields? default is for source.
+
+	tracer *tracer       // records the decisions for Explain, nil if not tracing
+	dryRun *DryRunResult // copy to a clone of target, see WithDryRun
 }
 
 // SourceValueExtractor provides a hook for handling
 // the extraction from source field.
 //
-// SourceValueExtractor can work for non-nested struct.
+// SourceValueExtractor can work for non-nested struct, see also
+// SourcePathExtractor.
 type SourceValueExtractor func(targetName string) typ.Any
 
 // TargetValueSetter provide a hook for handling the setup
@@ -70,16 +107,22 @@ type TargetValueSetter func(value *reflect.Value, sourceNames ...string) (err er
 
 // CopyTo makes a deep clone of a source object or merges it into the target.
 func (c *cpController) CopyTo(fromObjOrPtr, toObjPtr interface{}, opts ...Opt) (err error) { //nolint:revive
-	if fromObjOrPtr == nil || toObjPtr == nil {
-		return
-	}
-
 	lazyInitRoutines()
 
 	for _, opt := range opts {
 		opt(c)
 	}
 
+	if result := c.dryRun; result != nil {
+		c.dryRun = nil // one-shot, don't leave it in a shared controller
+		err = c.dryRunTo(result, fromObjOrPtr, toObjPtr)
+		return
+	}
+
+	if fromObjOrPtr == nil || toObjPtr == nil {
+		return
+	}
+
 	var (
 		from0 = reflect.ValueOf(fromObjOrPtr)
 		to0   = reflect.ValueOf(toObjPtr)
@@ -88,6 +131,18 @@ func (c *cpController) CopyTo(fromObjOrPtr, toObjPtr interface{}, opts ...Opt) (
 		root  = newParams(withOwners(c, nil, &from0, &to0, &from, &to))
 	)
 
+	if from0.Kind() == reflect.Ptr && to0.Kind() == reflect.Ptr {
+		// so that a back-pointer to the root object can be mapped
+		defer root.registerAlias(from0, to0)()
+	}
+
+	c.lockReport(from0.Type())
+
+	if c.pathExtractor != nil && to.Kind() == reflect.Struct {
+		err = c.copyFromPathExtractor(root, nil, nil, to)
+		return
+	}
+
 	dbglog.Log("          flags: %v", c.flags)
 	dbglog.Log("flags (verbose): %+v", c.flags)
 	dbglog.Log("      from.type: %v | input: %v", ref.Typfmtv(&from), ref.Typfmtv(&from0))
@@ -100,6 +155,15 @@ func (c *cpController) CopyTo(fromObjOrPtr, toObjPtr interface{}, opts ...Opt) (
 func (c *cpController) copyTo(params *Params, from, to reflect.Value) (err error) { //nolint:revive
 	err = c.copyToInternal(params, from, to,
 		func(c *cpController, params *Params, from, to reflect.Value) (err error) {
+			if processed, e := c.copyToInterfaceImpl(params, from, to, ""); processed {
+				err = e // an interface target resolved by the discriminator in source map
+				return
+			}
+			if processed, e := c.copyCollection(params, from, to); processed {
+				err = e // iterator source, or user collection target
+				return
+			}
+
 			kind, pkgPath := from.Kind(), from.Type().PkgPath()
 			if c.sourceExtractor != nil && to.IsValid() && !ref.IsNil(to) {
 				// use tool.IsNil because we are checking for:
@@ -141,6 +205,11 @@ func (c *cpController) copyToInternal( //nolint:revive,gocognit //yes, it is an
 		return
 	}
 
+	if c.shareable(params, from, to) {
+		to.Set(from) // an immutable value, see RegisterImmutableType
+		return
+	}
+
 	if c.testCloneable(params, from, to) {
 		dbglog.Log(`from -> to was Clone'd.`)
 		return
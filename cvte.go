@@ -0,0 +1,398 @@
+package evendeep
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/internal/tool"
+	"github.com/hedzr/evendeep/times"
+)
+
+// CvtE is the error-returning variant of Cvt.
+//
+// Each method converts data with the same loose rules as the
+// corresponding method of Cvt, but returns a *ConversionError
+// (naming the input and the target type) if data, or an element of
+// it, cannot be parsed or is out of range. So `CvtE.Int("12abc")`
+// fails while `CvtE.Int("0")` returns 0.
+//
+// The result is zero if err isn't nil.
+type CvtE struct{}
+
+func (s *CvtE) String(data any) (string, error)        { return cvtE(data, anyToString) }
+func (s *CvtE) StringSlice(data any) ([]string, error) { return cvtE(data, anyToStringSlice) }
+func (s *CvtE) StringMap(data any) (map[string]string, error) {
+	return cvtE(data, anyToStringMap)
+}
+
+func (s *CvtE) Bool(data any) (bool, error)                 { return cvtE(data, anyToBool) }
+func (s *CvtE) BoolSlice(data any) ([]bool, error)          { return cvtE(data, anyToBoolSlice) }
+func (s *CvtE) BoolMap(data any) (map[string]bool, error)   { return cvtE(data, anyToBoolMap) }
+func (s *CvtE) Int(data any) (int64, error)                 { return cvtE(data, anyToInt) }
+func (s *CvtE) Int64Slice(data any) ([]int64, error)        { return cvtE(data, anyToIntSliceT[int64]) }
+func (s *CvtE) Int32Slice(data any) ([]int32, error)        { return cvtE(data, anyToIntSliceT[int32]) }
+func (s *CvtE) Int16Slice(data any) ([]int16, error)        { return cvtE(data, anyToIntSliceT[int16]) }
+func (s *CvtE) Int8Slice(data any) ([]int8, error)          { return cvtE(data, anyToIntSliceT[int8]) }
+func (s *CvtE) IntSlice(data any) ([]int, error)            { return cvtE(data, anyToIntSliceT[int]) }
+func (s *CvtE) Int64Map(data any) (map[string]int64, error) { return cvtE(data, anyToInt64MapT[int64]) }
+func (s *CvtE) Int32Map(data any) (map[string]int32, error) { return cvtE(data, anyToInt64MapT[int32]) }
+func (s *CvtE) Int16Map(data any) (map[string]int16, error) { return cvtE(data, anyToInt64MapT[int16]) }
+func (s *CvtE) Int8Map(data any) (map[string]int8, error)   { return cvtE(data, anyToInt64MapT[int8]) }
+func (s *CvtE) IntMap(data any) (map[string]int, error)     { return cvtE(data, anyToInt64MapT[int]) }
+
+func (s *CvtE) Uint(data any) (uint64, error)          { return cvtE(data, anyToUint) }
+func (s *CvtE) Uint64Slice(data any) ([]uint64, error) { return cvtE(data, anyToUintSliceT[uint64]) }
+func (s *CvtE) Uint32Slice(data any) ([]uint32, error) { return cvtE(data, anyToUintSliceT[uint32]) }
+func (s *CvtE) Uint16Slice(data any) ([]uint16, error) { return cvtE(data, anyToUintSliceT[uint16]) }
+func (s *CvtE) Uint8Slice(data any) ([]uint8, error)   { return cvtE(data, anyToUintSliceT[uint8]) }
+func (s *CvtE) UintSlice(data any) ([]uint, error)     { return cvtE(data, anyToUintSliceT[uint]) }
+func (s *CvtE) Uint64Map(data any) (map[string]uint64, error) {
+	return cvtE(data, anyToUint64MapT[uint64])
+}
+func (s *CvtE) Uint32Map(data any) (map[string]uint32, error) {
+	return cvtE(data, anyToUint64MapT[uint32])
+}
+func (s *CvtE) Uint16Map(data any) (map[string]uint16, error) {
+	return cvtE(data, anyToUint64MapT[uint16])
+}
+func (s *CvtE) Uint8Map(data any) (map[string]uint8, error) {
+	return cvtE(data, anyToUint64MapT[uint8])
+}
+func (s *CvtE) UintMap(data any) (map[string]uint, error) { return cvtE(data, anyToUint64MapT[uint]) }
+
+func (s *CvtE) Float64(data any) (float64, error)        { return cvtE(data, anyToFloat[float64]) }
+func (s *CvtE) Float32(data any) (float32, error)        { return cvtE(data, anyToFloat[float32]) }
+func (s *CvtE) Float64Slice(data any) ([]float64, error) { return cvtE(data, anyToFloatSlice[float64]) }
+func (s *CvtE) Float32Slice(data any) ([]float32, error) { return cvtE(data, anyToFloatSlice[float32]) }
+func (s *CvtE) Float64Map(data any) (map[string]float64, error) {
+	return cvtE(data, anyToFloat64Map[float64])
+}
+func (s *CvtE) Float32Map(data any) (map[string]float32, error) {
+	return cvtE(data, anyToFloat64Map[float32])
+}
+
+func (s *CvtE) Complex128(data any) (complex128, error) { return cvtE(data, anyToComplex[complex128]) }
+func (s *CvtE) Complex64(data any) (complex64, error)   { return cvtE(data, anyToComplex[complex64]) }
+func (s *CvtE) Complex128Slice(data any) ([]complex128, error) {
+	return cvtE(data, anyToComplexSlice[complex128])
+}
+func (s *CvtE) Complex64Slice(data any) ([]complex64, error) {
+	return cvtE(data, anyToComplexSlice[complex64])
+}
+func (s *CvtE) Complex128Map(data any) (map[string]complex128, error) {
+	return cvtE(data, anyToComplexMap[complex128])
+}
+func (s *CvtE) Complex64Map(data any) (map[string]complex64, error) {
+	return cvtE(data, anyToComplexMap[complex64])
+}
+
+func (s *CvtE) Duration(data any) (time.Duration, error) { return cvtE(data, anyToDuration) }
+func (s *CvtE) DurationSlice(data any) ([]time.Duration, error) {
+	return cvtE(data, anyToDurationSlice)
+}
+func (s *CvtE) DurationMap(data any) (map[string]time.Duration, error) {
+	return cvtE(data, anyToDurationMap)
+}
+
+func (s *CvtE) Time(data any) (time.Time, error)               { return cvtE(data, anyToTime) }
+func (s *CvtE) TimeSlice(data any) ([]time.Time, error)        { return cvtE(data, anyToTimeSlice) }
+func (s *CvtE) TimeMap(data any) (map[string]time.Time, error) { return cvtE(data, anyToTimeMap) }
+
+// As converts data to T with the loose rules of Cvt, and returns an
+// error like CvtE does:
+//
+//	port, err := evendeep.As[uint16]("8080")
+//	tags, err := evendeep.As[[]string]("[a, b]")
+//	ttl, err := evendeep.As[map[string]time.Duration](cfg)
+//
+// T can be a result type of the CvtE methods, or any bool, integer,
+// float, complex or string type. The others are copied by
+// New().CopyTo(data, &ret).
+func As[T any](data any) (ret T, err error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if fn, ok := asConverters[typ]; ok {
+		var v any
+		if v, err = fn(data); err == nil {
+			ret, _ = v.(T)
+		}
+		return
+	}
+
+	rv, ok, err := asScalar(data, typ)
+	if !ok {
+		err = New().CopyTo(data, &ret)
+		return
+	}
+	if err == nil {
+		ret, _ = rv.Interface().(T)
+	}
+	return
+}
+
+// asScalar converts data to a bool, integer, float, complex or string
+// typ as As does. It returns ok = false for the other types.
+func asScalar(data any, typ reflect.Type) (rv reflect.Value, ok bool, err error) {
+	var v any
+	switch k := typ.Kind(); {
+	case k == reflect.String:
+		v, err = cvtE(data, anyToString)
+	case k == reflect.Bool:
+		v, err = cvtE(data, anyToBool)
+	case isIntKind(k):
+		v, err = cvtE(data, anyToInt)
+	case isUintKind(k):
+		v, err = cvtE(data, anyToUint)
+	case isFloatKind(k):
+		v, err = cvtE(data, anyToFloat[float64])
+	case k == reflect.Complex64 || k == reflect.Complex128:
+		v, err = cvtE(data, anyToComplex[complex128])
+	default:
+		return
+	}
+	ok = true
+	if err == nil {
+		rv, err = convertNumber(reflect.ValueOf(v), typ, numConvStrict)
+	}
+	if ce, yes := err.(*ConversionError); yes { //nolint:errorlint //it's returned directly
+		ce.Value, ce.Target = data, typ
+	}
+	return
+}
+
+//nolint:gochecknoglobals //i know that
+var asConverters = func() map[reflect.Type]func(data any) (any, error) {
+	var c CvtE
+	m := make(map[reflect.Type]func(data any) (any, error))
+	for _, fn := range []any{
+		c.String, c.StringSlice, c.StringMap,
+		c.Bool, c.BoolSlice, c.BoolMap,
+		c.Int, c.Int64Slice, c.Int32Slice, c.Int16Slice, c.Int8Slice, c.IntSlice,
+		c.Int64Map, c.Int32Map, c.Int16Map, c.Int8Map, c.IntMap,
+		c.Uint, c.Uint64Slice, c.Uint32Slice, c.Uint16Slice, c.Uint8Slice, c.UintSlice,
+		c.Uint64Map, c.Uint32Map, c.Uint16Map, c.Uint8Map, c.UintMap,
+		c.Float64, c.Float32, c.Float64Slice, c.Float32Slice, c.Float64Map, c.Float32Map,
+		c.Complex128, c.Complex64, c.Complex128Slice, c.Complex64Slice, c.Complex128Map, c.Complex64Map,
+		c.Duration, c.DurationSlice, c.DurationMap,
+		c.Time, c.TimeSlice, c.TimeMap,
+	} {
+		fv := reflect.ValueOf(fn)
+		m[fv.Type().Out(0)] = func(data any) (any, error) {
+			out := fv.Call([]reflect.Value{reflect.ValueOf(&data).Elem()})
+			err, _ := out[1].Interface().(error)
+			return out[0].Interface(), err
+		}
+	}
+	return m
+}()
+
+//nolint:gochecknoglobals //i know that
+var (
+	durationType = reflect.TypeOf((*time.Duration)(nil)).Elem()
+	timeType     = reflect.TypeOf((*time.Time)(nil)).Elem()
+)
+
+// stringToFalseMap holds the words which are false surely, the
+// others not in stringToBoolMap are false too, but it's an error
+// in CvtE.
+//
+//nolint:gochecknoglobals //i know that
+var stringToFalseMap = map[string]struct{}{
+	"0":      {},
+	"f":      {},
+	"female": {},
+	"n":      {},
+	"no":     {},
+	"false":  {},
+	"bad":    {},
+	"deny":   {},
+	"off":    {},
+	"close":  {},
+}
+
+// cvtE converts data by the loose converter conv, after checking
+// data can be converted to the result type.
+func cvtE[R any](data any, conv func(data any) R) (ret R, err error) {
+	if err = checkCvt(data, reflect.TypeOf((*R)(nil)).Elem()); err == nil {
+		ret = conv(data)
+	}
+	return
+}
+
+// checkCvt tests if data can be converted to typ with the loose rules
+// of Cvt.
+func checkCvt(data any, typ reflect.Type) (err error) {
+	if data == nil {
+		return
+	}
+	switch typ.Kind() { //nolint:exhaustive //others are scalars
+	case reflect.Slice:
+		err = checkCvtSlice(data, typ)
+	case reflect.Map:
+		err = checkCvtMap(data, typ)
+	default:
+		err = checkCvtScalar(data, typ)
+	}
+	return
+}
+
+func checkCvtSlice(data any, typ reflect.Type) (err error) {
+	et := typ.Elem()
+	if rv := reflect.ValueOf(data); rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		ec := errors.New("cannot convert %v (%T) to %v", data, data, typ)
+		defer ec.Defer(&err)
+		for i := 0; i < rv.Len(); i++ {
+			ec.Attach(checkCvtScalar(rv.Index(i).Interface(), et))
+		}
+		return
+	}
+
+	ins := strings.TrimSpace(anyToString(data))
+	if len(ins) < 2 || ins[0] != '[' || ins[len(ins)-1] != ']' {
+		return checkCvtScalar(data, et) // a single element
+	}
+	if strings.TrimSpace(ins[1:len(ins)-1]) == "" {
+		return
+	}
+	ec := errors.New("cannot convert %q to %v", ins, typ)
+	defer ec.Defer(&err)
+	for _, it := range strings.Split(ins[1:len(ins)-1], ",") {
+		v := strings.TrimSpace(tool.TrimQuotes(strings.TrimSpace(it)))
+		ec.Attach(checkCvtScalar(v, et))
+	}
+	return
+}
+
+func checkCvtMap(data any, typ reflect.Type) (err error) {
+	et := typ.Elem()
+	if rv := reflect.ValueOf(data); rv.Kind() == reflect.Map {
+		ec := errors.New("cannot convert %v (%T) to %v", data, data, typ)
+		defer ec.Defer(&err)
+		for it := rv.MapRange(); it.Next(); {
+			ec.Attach(checkCvtScalar(it.Value().Interface(), et))
+		}
+		return
+	}
+
+	ins := strings.TrimSpace(anyToString(data))
+	if len(ins) < 2 || ins[0] != '{' || ins[len(ins)-1] != '}' {
+		return &ConversionError{Value: data, Target: typ, Err: ErrBadSyntax}
+	}
+	if strings.TrimSpace(ins[1:len(ins)-1]) == "" {
+		return
+	}
+	ec := errors.New("cannot convert %q to %v", ins, typ)
+	defer ec.Defer(&err)
+	for _, it := range strings.Split(ins[1:len(ins)-1], ",") {
+		kv := strings.Split(it, ":")
+		if len(kv) != 2 { //nolint:gomnd //key and value
+			ec.Attach(&ConversionError{Value: it, Target: typ, Err: ErrBadSyntax})
+			continue
+		}
+		v := strings.TrimSpace(tool.TrimQuotes(strings.TrimSpace(kv[1])))
+		ec.Attach(checkCvtScalar(v, et))
+	}
+	return
+}
+
+func checkCvtScalar(data any, typ reflect.Type) (err error) {
+	if data == nil {
+		return
+	}
+	var reason error
+	if !cvtDirect(data, typ) {
+		reason = cvtParse(anyToString(data), typ)
+	}
+	if reason == nil {
+		reason = cvtRange(data, typ)
+	}
+	if reason != nil {
+		err = &ConversionError{Value: data, Target: typ, Err: reason}
+	}
+	return
+}
+
+// cvtDirect tests if the loose converters take data as a number or a
+// time value directly, rather than parsing its string form.
+func cvtDirect(data any, typ reflect.Type) bool {
+	dt := reflect.TypeOf(data)
+	k := dt.Kind()
+	basic := dt.PkgPath() == "" && dt.Name() != "" // the predeclared types
+	numeric := basic && (isNumberKind(k) || k == reflect.Complex64 || k == reflect.Complex128)
+
+	switch tk := typ.Kind(); {
+	case typ == durationType:
+		return dt == durationType || (basic && (isIntKind(k) || isUintKind(k)))
+	case typ == timeType:
+		return dt == timeType || dt == reflect.PointerTo(timeType) || numeric || (basic && k == reflect.Bool)
+	case tk == reflect.String:
+		return true
+	case tk == reflect.Bool:
+		return basic && k == reflect.Bool
+	case isIntKind(tk), isUintKind(tk):
+		return numeric || dt == durationType || dt == timeType
+	default:
+		return numeric
+	}
+}
+
+// cvtParse tests if the string s can be parsed to typ.
+func cvtParse(s string, typ reflect.Type) (reason error) {
+	var err error
+	switch tk := typ.Kind(); {
+	case typ == durationType:
+		if _, err = strconv.ParseInt(s, 10, 64); err != nil {
+			_, err = times.ParseDuration(s)
+		}
+	case typ == timeType:
+		_, err = times.SmartParseTime(s)
+	case tk == reflect.Bool:
+		ls := strings.ToLower(s)
+		if _, ok := stringToBoolMap[ls]; ok {
+			return
+		}
+		if _, ok := stringToFalseMap[ls]; ok {
+			return
+		}
+		_, err = strconv.ParseFloat(s, 64)
+	case isUintKind(tk):
+		var f float64
+		if f, err = strconv.ParseFloat(s, 64); err == nil && f < 0 {
+			return ErrNegativeToUnsigned
+		}
+	case isIntKind(tk), isFloatKind(tk):
+		_, err = strconv.ParseFloat(s, 64)
+	case tk == reflect.Complex64 || tk == reflect.Complex128:
+		_, err = strconv.ParseComplex(s, 128)
+	}
+	if err != nil {
+		reason = ErrBadSyntax
+		if isRangeError(err) {
+			reason = ErrOverflow
+		}
+	}
+	return
+}
+
+// cvtRange tests if the integer value of data fits typ.
+func cvtRange(data any, typ reflect.Type) (reason error) {
+	tk := typ.Kind()
+	switch {
+	case isIntKind(tk) && typ != durationType:
+		if u, ok := data.(uint64); ok && u > math.MaxInt64 {
+			return ErrOverflow
+		}
+		if typ.Bits() < 64 { //nolint:gomnd //narrower
+			_, err := convertNumber(reflect.ValueOf(anyToInt(data)), typ, numConvStrict)
+			reason = errors.Unwrap(err)
+		}
+	case isUintKind(tk) && typ.Bits() < 64: //nolint:gomnd //narrower
+		_, err := convertNumber(reflect.ValueOf(anyToUint(data)), typ, numConvStrict)
+		reason = errors.Unwrap(err)
+	}
+	return
+}
@@ -0,0 +1,81 @@
+package evendeep_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestCvtE(t *testing.T) {
+	var cvt evendeep.CvtE
+
+	for i, c := range []struct {
+		conv   func(data any) (any, error)
+		src    any
+		expect any
+		err    error
+	}{
+		{func(d any) (any, error) { return cvt.Int(d) }, "0", int64(0), nil},
+		{func(d any) (any, error) { return cvt.Int(d) }, "12", int64(12), nil},
+		{func(d any) (any, error) { return cvt.Int(d) }, 3.13, int64(3), nil},
+		{func(d any) (any, error) { return cvt.Int(d) }, "12abc", int64(0), evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.Int(d) }, uint64(1 << 63), int64(0), evendeep.ErrOverflow},
+		{func(d any) (any, error) { return cvt.Uint(d) }, "-1", uint64(0), evendeep.ErrNegativeToUnsigned},
+		{func(d any) (any, error) { return cvt.Bool(d) }, "off", false, nil},
+		{func(d any) (any, error) { return cvt.Bool(d) }, "maybe", false, evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.Float64(d) }, "3.5", 3.5, nil},
+		{func(d any) (any, error) { return cvt.Complex128(d) }, "(1+2i)", 1 + 2i, nil},
+		{func(d any) (any, error) { return cvt.Duration(d) }, "1h", time.Hour, nil},
+		{func(d any) (any, error) { return cvt.Duration(d) }, "1.5", time.Duration(0), evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.Time(d) }, "tomorrow", time.Time{}, evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.String(d) }, 1.5, "1.5", nil},
+
+		{func(d any) (any, error) { return cvt.IntSlice(d) }, "[1, 2]", []int{1, 2}, nil},
+		{func(d any) (any, error) { return cvt.IntSlice(d) }, []string{"1", "x"}, []int(nil), evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.Int8Slice(d) }, []int{1, 300}, []int8(nil), evendeep.ErrOverflow},
+		{func(d any) (any, error) { return cvt.Float32Map(d) }, map[string]any{"a": "1.5"}, map[string]float32{"a": 1.5}, nil},
+		{func(d any) (any, error) { return cvt.DurationMap(d) }, "{a: 1s, b: soon}", map[string]time.Duration(nil), evendeep.ErrBadSyntax},
+		{func(d any) (any, error) { return cvt.BoolMap(d) }, "true", map[string]bool(nil), evendeep.ErrBadSyntax},
+	} {
+		actual, err := c.conv(c.src)
+		if c.err == nil && err != nil {
+			t.Fatalf("%5d. unexpected error %v | src is %v", i, err, c.src)
+		} else if c.err != nil && !errors.Is(err, c.err) {
+			t.Fatalf("%5d. expecting error %v, but got %v | src is %v", i, c.err, err, c.src)
+		}
+		if !reflect.DeepEqual(actual, c.expect) {
+			t.Fatalf("%5d. expecting %v, but got %v | src is %v", i, c.expect, actual, c.src)
+		}
+		if err != nil {
+			t.Logf("%5d. %v", i, err)
+		}
+	}
+}
+
+func TestAs(t *testing.T) {
+	if v, err := evendeep.As[uint16]("8080"); err != nil || v != 8080 {
+		t.Fatalf("want 8080, got %v, %v", v, err)
+	}
+	if v, err := evendeep.As[[]string]("[a, b]"); err != nil || !reflect.DeepEqual(v, []string{"a", "b"}) {
+		t.Fatalf("want [a b], got %v, %v", v, err)
+	}
+	if v, err := evendeep.As[map[string]time.Duration](map[string]any{"ttl": "1m"}); err != nil || v["ttl"] != time.Minute {
+		t.Fatalf("want 1m, got %v, %v", v, err)
+	}
+
+	_, err := evendeep.As[int8]("300")
+	var ce *evendeep.ConversionError
+	if !errors.As(err, &ce) || !errors.Is(err, evendeep.ErrOverflow) || ce.Target != reflect.TypeOf(int8(0)) {
+		t.Fatalf("want an overflow error to int8, got %v", err)
+	}
+	t.Log(err)
+
+	type point struct{ X, Y int }
+	if v, err := evendeep.As[point](map[string]any{"X": 1, "Y": 2}); err != nil || v != (point{1, 2}) {
+		t.Fatalf("want {1 2}, got %v, %v", v, err)
+	}
+}
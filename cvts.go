@@ -9,7 +9,6 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 	"unicode"
 
@@ -19,8 +18,8 @@ import (
 	"github.com/hedzr/evendeep/flags"
 	"github.com/hedzr/evendeep/flags/cms"
 	"github.com/hedzr/evendeep/internal/syscalls"
-	"github.com/hedzr/evendeep/internal/times"
 	"github.com/hedzr/evendeep/ref"
+	"github.com/hedzr/evendeep/times"
 	"github.com/hedzr/evendeep/typ"
 	logz "github.com/hedzr/logg/slog"
 )
@@ -54,6 +53,9 @@ func initConverters() {
 	defValueConverters = ValueConverters{ // Transform()
 		&fromStringConverter{}, // the final choice here
 		&toStringConverter{},
+		&enumConverter{},
+		&unmarshalerConverter{},
+		&nullConverter{},
 
 		// &toFuncConverter{},
 		&fromFuncConverter{},
@@ -70,6 +72,9 @@ func initConverters() {
 	defValueCopiers = ValueCopiers{ // CopyTo()
 		&fromStringConverter{}, // the final choice here
 		&toStringConverter{},
+		&enumConverter{},
+		&unmarshalerConverter{},
+		&nullConverter{},
 
 		&toFuncConverter{},
 		&fromFuncConverter{},
@@ -1072,15 +1077,15 @@ func anyToTime(data any) (tm time.Time) { //nolint:revive
 		return TimestampFromFloat64(float64(real(z)))
 
 	case string:
-		return mustSmartParseTime(z)
+		return times.MustSmartParseTime(z)
 	case fmt.Stringer:
-		return mustSmartParseTime(z.String())
+		return times.MustSmartParseTime(z.String())
 	case any:
-		return mustSmartParseTime(anyToString(z))
+		return times.MustSmartParseTime(anyToString(z))
 
 	default:
 		str := fmt.Sprintf("%v", data)
-		return mustSmartParseTime(str)
+		return times.MustSmartParseTime(str)
 	}
 }
 
@@ -1125,78 +1130,6 @@ func TimestampFromFloat64(ts float64) time.Time {
 	// return time.Unix(secs, nsecs)
 }
 
-// mustSmartParseTime parses a formatted string and returns the time value it represents.
-func mustSmartParseTime(str string) (tm time.Time) {
-	tm, _ = smartParseTime(str)
-	return
-}
-
-func smartParseTime(str string) (tm time.Time, err error) {
-	for _, layout := range onceInitTimeFormats() {
-		if tm, err = time.Parse(layout, str); err == nil {
-			break
-		}
-	}
-	return
-}
-
-var (
-	knownDateTimeFormats []string
-	onceFormats          sync.Once
-)
-
-func onceInitTimeFormats() []string { //nolint:revive
-	onceFormats.Do(func() {
-		knownDateTimeFormats = []string{
-			"2006-01-02 15:04:05.999999999 -0700",
-			"2006-01-02 15:04:05.999999999Z07:00",
-			"2006-01-02 15:04:05.999999999",
-			"2006-01-02 15:04:05.999",
-			"2006-01-02 15:04:05",
-			"2006-01-02",
-			"2006/01/02",
-			"01/02/2006",
-			"01-02",
-
-			"2006-1-2 15:4:5.999999999 -0700",
-			"2006-1-2 15:4:5.999999999Z07:00",
-			"2006-1-2 15:4:5.999999999",
-			"2006-1-2 15:4:5.999",
-			"2006-1-2 15:4:5",
-			"2006-1-2",
-			"2006/1/2",
-			"1/2/2006",
-			"1-2",
-
-			"15:04:05.999999999",
-			"15:04.999999999",
-			"15:04:05.999",
-			"15:04.999",
-			"15:04:05",
-			"15:04",
-
-			"15:4:5.999999999",
-			"15:4.999999999",
-			"15:4:5.999",
-			"15:4.999",
-			"15:4:5",
-			"15:4",
-
-			time.RFC3339,
-			time.RFC3339Nano,
-			time.RFC1123Z,
-			time.RFC1123,
-			time.RFC850,
-			time.RFC822Z,
-			time.RFC822,
-			time.RubyDate,
-			time.UnixDate,
-			time.ANSIC,
-		}
-	})
-	return knownDateTimeFormats
-}
-
 func (s *Cvt) TimeSlice(data any) []time.Time { return anyToTimeSlice(data) }
 
 func anyToTimeSlice(data any) (ret []time.Time) { //nolint:revive
@@ -1234,13 +1167,13 @@ func anyToTimeSlice(data any) (ret []time.Time) { //nolint:revive
 	case []string:
 		ret = make([]time.Time, 0, len(z))
 		for _, it := range z {
-			ret = append(ret, mustSmartParseTime(it))
+			ret = append(ret, times.MustSmartParseTime(it))
 		}
 		return
 	case []fmt.Stringer:
 		ret = make([]time.Time, 0, len(z))
 		for _, it := range z {
-			ret = append(ret, mustSmartParseTime(it.String()))
+			ret = append(ret, times.MustSmartParseTime(it.String()))
 		}
 		return
 
@@ -1285,13 +1218,13 @@ func anyToTimeMap(data any) (ret map[string]time.Time) { //nolint:revive
 	case map[string]string:
 		ret = make(map[string]time.Time, len(z))
 		for k, v := range z {
-			ret[k] = mustSmartParseTime(v)
+			ret[k] = times.MustSmartParseTime(v)
 		}
 		return
 	case map[string]fmt.Stringer:
 		ret = make(map[string]time.Time, len(z))
 		for k, v := range z {
-			ret[k] = mustSmartParseTime(v.String())
+			ret[k] = times.MustSmartParseTime(v.String())
 		}
 		return
 	case map[string]any:
@@ -1827,6 +1760,10 @@ func (c *fromStringConverter) CopyTo(ctx *ValueConverterContext, source, target
 		return
 	}
 
+	if isConversionError(e) || errors.Is(e, ErrUnknownName) {
+		err = e
+		return
+	}
 	if !errors.Is(e, &strconv.NumError{Err: strconv.ErrSyntax}) && !errors.IsAnyOf(e, strconv.ErrSyntax, strconv.ErrRange) {
 		dbglog.Log("  Transform() failed: %v", e)
 		dbglog.Log("  try running postCopyTo()")
@@ -1852,6 +1789,23 @@ func (c *fromStringConverter) Transform(ctx *ValueConverterContext, source refle
 		return
 	}
 
+	if k := targetType.Kind(); ctx != nil && k != reflect.Uintptr && isNumberKind(k) && source.Kind() == reflect.String {
+		format, e := ctx.numberFormat()
+		if e != nil {
+			err = e
+			return
+		}
+		if format != nil {
+			if plain, ok := format.Normalize(source.String()); ok {
+				source = reflect.ValueOf(plain)
+			}
+		}
+		if mode := ctx.numConvMode(); mode != numConvWrap {
+			target, err = parseNumber(source.String(), targetType, mode)
+			return
+		}
+	}
+
 	switch k := targetType.Kind(); k { //nolint:exhaustive //no need
 	case reflect.Bool:
 		target = rToBool(source)
@@ -1945,6 +1899,8 @@ func (c *fromMapConverter) CopyTo(ctx *ValueConverterContext, source, target ref
 			err = ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&ret), ref.Typfmtv(&ret))
 		}
 		dbglog.Log("  tgt: %v (ret = %v)", ref.Valfmt(&tgt), ref.Valfmt(&ret))
+	} else if errors.IsAnyOf(e, ErrUnknownInterfaceImpl, ErrUnknownName) || isConversionError(e) {
+		err = e
 	} else if !errors.Is(e, strconv.ErrSyntax) && !errors.Is(e, strconv.ErrRange) {
 		dbglog.Log("  Transform() failed: %v", e)
 		dbglog.Log("  try running postCopyTo()")
@@ -2211,6 +2167,11 @@ func (c *fromMapConverter) toStruct(ctx *ValueConverterContext, source reflect.V
 		tsft := tsf.Type
 		tsfk := tsft.Kind()
 		if tsfk == reflect.Interface {
+			disc := parseFieldTags(tsf.Tag, cc.tagKeyName).discriminator
+			if processed, e := cc.copyToInterfaceImpl(ctx.Params, src, fld, disc); processed {
+				ec.Attach(e)
+				continue
+			}
 			// tsft = tsft.Elem()
 			fld = fld.Elem()
 		} else if tsfk == reflect.Ptr {
@@ -2225,7 +2186,16 @@ func (c *fromMapConverter) toStruct(ctx *ValueConverterContext, source reflect.V
 			dbglog.Log("  fld.%q: %v (%v)", ks, ref.Valfmt(&fld), ref.Typfmtv(&fld))
 		}
 
-		err = ctx.controller.copyTo(ctx.Params, src, fld)
+		if ctx.Params != nil {
+			// for the tags of field, such as the discriminator for the
+			// interface elements of a slice or map field
+			saved := ctx.Params.fieldTag
+			ctx.Params.fieldTag = tsf.Tag
+			err = ctx.controller.copyTo(ctx.Params, src, fld)
+			ctx.Params.fieldTag = saved
+		} else {
+			err = ctx.controller.copyTo(ctx.Params, src, fld)
+		}
 		dbglog.Log("  nv.%q: %v (%v) ", ks, ref.Valfmt(&fld), ref.Typfmtv(&fld))
 		ec.Attach(err)
 
@@ -2260,39 +2230,6 @@ func (c *fromMapConverter) Match(params *Params, source, target reflect.Type) (c
 
 //
 
-//
-
-// fromSyncPkgConverter provides default actions for all entities
-// in sync package, such as sync.Pool, sync.RWMutex, and so on.
-//
-// By default, these entities should NOT be copied from one to another
-// one. So our default actions are empty.
-type fromSyncPkgConverter struct{ fromConverterBase } //nolint:unused
-
-//nolint:lll //keep it
-func (c *fromSyncPkgConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
-	// st.PkgPath() . st.Name()
-	if yes = source.Kind() == reflect.Struct && strings.HasPrefix(source.String(), "sync."); yes {
-		ctx = &ValueConverterContext{params}
-		dbglog.Log("    src: %v, tgt: %v | Matched", source, target)
-		// } else {
-		// dbglog.Log("    src: %v, tgt: %v", source, target)
-	}
-	return
-}
-
-func (c *fromSyncPkgConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) { //nolint:revive,lll
-	_, _, _ = ctx, source, target
-	return
-}
-
-func (c *fromSyncPkgConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) { //nolint:revive,lll
-	_, _, _ = ctx, source, target
-	return
-}
-
-//
-
 type fromBytesBufferConverter struct{ fromConverterBase }
 
 func (c *fromBytesBufferConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
@@ -2392,6 +2329,10 @@ func (c *fromTimeConverter) CopyTo(ctx *ValueConverterContext, source, target re
 		return
 	}
 
+	if errors.Is(e, ErrUnknownName) {
+		err = e // such as an unknown epoch unit
+		return
+	}
 	dbglog.Log("  Transform() failed: %v", e)
 	dbglog.Log("              trying to postCopyTo()")
 	err = c.postCopyTo(ctx, source, target)
@@ -2406,33 +2347,44 @@ func (c *fromTimeConverter) Transform(ctx *ValueConverterContext, source reflect
 			return
 		}
 
+		var params *Params
+		if ctx != nil {
+			params = ctx.Params
+		}
+
 		switch k := targetType.Kind(); k { //nolint:exhaustive //no need
 		case reflect.Bool:
 			b := ref.IsNil(source) || ref.IsZero(source)
 			target = reflect.ValueOf(b)
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			tm, _ := source.Interface().(time.Time) //nolint:revive,errcheck //no need
-			t := reflect.ValueOf(tm.Unix())
-			target, err = rToInteger(t, targetType)
+			var n int64
+			if n, err = params.toEpoch(tm); err == nil {
+				target, err = rToInteger(reflect.ValueOf(n), targetType)
+			}
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 			tm, _ := source.Interface().(time.Time) //nolint:revive,errcheck //no need
-			t := reflect.ValueOf(tm.Unix())
-			target, err = rToUInteger(t, targetType)
+			var n int64
+			if n, err = params.toEpoch(tm); err == nil {
+				target, err = rToUInteger(reflect.ValueOf(n), targetType)
+			}
 
 		case reflect.Float32, reflect.Float64:
 			tm, _ := source.Interface().(time.Time) //nolint:revive,errcheck //no need
-			f := float64(tm.UnixNano()) / 1e9       //nolint:gomnd //simple case
-			t := reflect.ValueOf(f)
-			target, err = rToFloat(t, targetType)
+			var f float64
+			if f, err = params.toEpochFloat(tm); err == nil {
+				target, err = rToFloat(reflect.ValueOf(f), targetType)
+			}
 		case reflect.Complex64, reflect.Complex128:
 			tm, _ := source.Interface().(time.Time) //nolint:revive,errcheck //no need
-			f := float64(tm.UnixNano()) / 1e9       //nolint:gomnd //simple case
-			t := reflect.ValueOf(f)
-			target, err = rToComplex(t, targetType)
+			var f float64
+			if f, err = params.toEpochFloat(tm); err == nil {
+				target, err = rToComplex(reflect.ValueOf(f), targetType)
+			}
 
 		case reflect.String:
 			tm, _ := source.Interface().(time.Time) //nolint:revive,errcheck //no need
-			str := tm.Format(time.RFC3339)
+			str := params.formatTime(tm)
 			t := reflect.ValueOf(str)
 			target, err = rToString(t, targetType)
 
@@ -2456,45 +2408,6 @@ func (c *fromTimeConverter) Match(params *Params, source, target reflect.Type) (
 
 //
 
-//nolint:gochecknoglobals //i know that
-var knownTimeLayouts = []string{
-	"2006-01-02 15:04:05.000000000",
-	"2006-01-02 15:04:05.000000",
-	"2006-01-02 15:04:05.000",
-	"2006-01-02 15:04:05",
-	"2006-01-02 15:04",
-	"2006-01-02",
-
-	"2006-01-02 15:04:05.999999999Z07:00",
-	"2006-01-02 15:04:05.999999999",
-	"2006-01-02 15:04:05Z07:00",
-	"2006-01-02 15:04:05",
-
-	time.RFC3339,
-
-	time.ANSIC,
-	time.UnixDate,
-	time.RubyDate,
-	time.RFC822,
-	time.RFC822Z,
-	time.RFC850,
-	time.RFC1123,
-	time.RFC1123Z,
-	time.RFC3339Nano,
-	time.Kitchen,
-	time.Stamp,
-	time.StampMilli,
-	time.StampMicro,
-	time.StampNano,
-
-	"01/02/2006 15:04:05.000000000",
-	"01/02/2006 15:04:05.000000",
-	"01/02/2006 15:04:05.000",
-	"01/02/2006 15:04:05",
-	"01/02/2006 15:04",
-	"01/02/2006",
-}
-
 type toTimeConverter struct{ toConverterBase }
 
 // func (c *toTimeConverter) fallback(target reflect.Value) (err error) {
@@ -2518,23 +2431,14 @@ func (c *toTimeConverter) CopyTo(ctx *ValueConverterContext, source, target refl
 
 	if ret, e := c.Transform(ctx, source, tgtType); e == nil {
 		target.Set(ret)
+	} else if errors.Is(e, ErrUnknownName) {
+		err = e // such as an unknown epoch unit
 	} else if ctx.isGroupedFlagOKDeeply(cms.ClearIfInvalid) {
 		err = c.fallback(target)
 	}
 	return
 }
 
-func tryParseTime(s string) (tm time.Time) {
-	var err error
-	for _, layout := range knownTimeLayouts {
-		tm, err = time.Parse(layout, s)
-		if err == nil {
-			return
-		}
-	}
-	return
-}
-
 //nolint:lll //keep it
 func (c *toTimeConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) {
 	if source.IsValid() { //nolint:gocritic // no need to switch to 'switch' clause
@@ -2543,25 +2447,28 @@ func (c *toTimeConverter) Transform(ctx *ValueConverterContext, source reflect.V
 			return
 		}
 
+		var params *Params
+		if ctx != nil {
+			params = ctx.Params
+		}
+
 		switch k := source.Kind(); k { //nolint:exhaustive //no need
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			tm := time.Unix(source.Int(), 0)
-			target = reflect.ValueOf(tm)
+			tm, e := params.fromEpoch(source.Int())
+			target, err = reflect.ValueOf(tm), e
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			tm := time.Unix(int64(source.Uint()), 0)
-			target = reflect.ValueOf(tm)
+			tm, e := params.fromEpoch(int64(source.Uint()))
+			target, err = reflect.ValueOf(tm), e
 
 		case reflect.Float32, reflect.Float64:
-			sec, dec := math.Modf(source.Float())
-			tm := time.Unix(int64(sec), int64(dec*(1e9)))
-			target = reflect.ValueOf(tm)
+			tm, e := params.fromEpochFloat(source.Float())
+			target, err = reflect.ValueOf(tm), e
 		case reflect.Complex64, reflect.Complex128:
-			sec, dec := math.Modf(real(source.Complex()))
-			tm := time.Unix(int64(sec), int64(dec*(1e9)))
-			target = reflect.ValueOf(tm)
+			tm, e := params.fromEpochFloat(real(source.Complex()))
+			target, err = reflect.ValueOf(tm), e
 
 		case reflect.String:
-			tm := tryParseTime(source.String())
+			tm, _ := params.parseTime(source.String()) //nolint:errcheck //zero time if it cannot be parsed
 			target = reflect.ValueOf(tm)
 
 		default:
@@ -2620,6 +2527,10 @@ func (c *fromDurationConverter) CopyTo(ctx *ValueConverterContext, source, targe
 		return
 	}
 
+	if errors.Is(e, ErrUnknownName) {
+		err = e // such as an unknown duration format
+		return
+	}
 	dbglog.Log("  Transform() failed: %v", e)
 	dbglog.Log("              trying to postCopyTo()")
 	err = c.postCopyTo(ctx, source, target)
@@ -2662,7 +2573,13 @@ func (c *fromDurationConverter) Transform(ctx *ValueConverterContext, source ref
 			target, err = rToComplex(source, targetType)
 
 		case reflect.String:
-			target, err = rToString(source, targetType)
+			if str, ok, e := ctx.formatDuration(source); e != nil {
+				err = e
+			} else if ok {
+				target, err = rToString(reflect.ValueOf(str), targetType)
+			} else {
+				target, err = rToString(source, targetType)
+			}
 
 		// reflect.Array
 		// reflect.Chan
@@ -2844,6 +2761,9 @@ func (c *toFuncConverter) copyTo(ctx *ValueConverterContext, source, src, tgt, t
 		}
 		err = copyToFuncImpl(controller, source, tgt, tgttyp)
 	} else if k == reflect.Func {
+		if src, err = ctx.adaptFuncTo(src, tgttyp); err != nil {
+			return
+		}
 		if !c.processUnexportedField(ctx, tgt, src) {
 			tsetter.Set(src)
 		}
@@ -2926,6 +2846,9 @@ func (c *fromFuncConverter) CopyTo(ctx *ValueConverterContext, source, target re
 		err = c.funcResultToTarget(ctx, src, target)
 		return
 	} else if k == reflect.Func {
+		if src, err = ctx.adaptFuncTo(src, tgtType); err != nil {
+			return
+		}
 		if !c.processUnexportedField(ctx, tgt, src) {
 			tgtptr.Set(src)
 		}
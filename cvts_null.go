@@ -0,0 +1,156 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/flags/cms"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// nullFields returns the index of the value field of a sql.Null*-like
+// type, which is a struct of a value field and a `Valid bool` field,
+// and implements sql.Scanner, such as sql.NullString, sql.NullTime and
+// sql.Null[T].
+func nullFields(typ reflect.Type) (value int, ok bool) {
+	if typ.Kind() != reflect.Struct || typ.NumField() != 2 || !reflect.PointerTo(typ).Implements(sqlScannerType) {
+		return
+	}
+	for i := 0; i < 2; i++ {
+		if f := typ.Field(i); f.Name == "Valid" && f.Type.Kind() == reflect.Bool {
+			return 1 - i, typ.Field(1 - i).IsExported()
+		}
+	}
+	return
+}
+
+func isNullType(typ reflect.Type) bool {
+	_, ok := nullFields(typ)
+	return ok
+}
+
+// isNullBridgeable tests if typ can be converted from/to a sql.Null*
+// type, that is, another sql.Null* type, or a plain value (such as
+// string, int64, []byte and time.Time).
+func isNullBridgeable(typ reflect.Type) bool {
+	switch typ.Kind() { //nolint:exhaustive //no need
+	case reflect.Struct:
+		return isNullType(typ) || packageisreserved(typ.PkgPath())
+	case reflect.Map, reflect.Interface, reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Invalid:
+		return false
+	}
+	return true
+}
+
+// nullConverter converts between the sql.Null* types, the pointers and
+// the plain values: Valid=false is a nil pointer or a zero value, and
+// Valid=true is the value.
+//
+// A zero value is converted to Valid=true unless WithZeroAsNull is
+// set.
+type nullConverter struct{ cvtbase }
+
+func (c *nullConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
+	tgt, tgtptr := ref.Rdecode(target)
+	tgtType := c.safeType(tgt, tgtptr) // because tgt might be invalid, so we fetch tgt type via its pointer
+	dbglog.Log("  target: %v (%v), tgtptr: %v, tgt: %v, tgttyp: %v",
+		ref.Typfmtv(&target), ref.Typfmt(target.Type()), ref.Typfmtv(&tgtptr),
+		ref.Typfmtv(&tgt), ref.Typfmt(tgtType))
+
+	if _, valid := c.nullValue(ctx, source); !valid && ctx != nil && ctx.isGroupedFlagOKDeeply(cms.OmitIfNil, cms.OmitIfEmpty) {
+		return // keep the target as is
+	}
+
+	var ret reflect.Value
+	if target.Kind() == reflect.Ptr && target.CanSet() {
+		// a pointer field, such as *string, is set to nil or a new pointer
+		if ret, err = c.Transform(ctx, source, target.Type()); err == nil {
+			target.Set(ret)
+		}
+		return
+	}
+	if ret, err = c.Transform(ctx, source, tgtType); err == nil {
+		if k := tgtptr.Kind(); k == reflect.Interface { //nolint:gocritic // no need to switch to 'switch' clause
+			tgtptr.Set(ret)
+		} else if k == reflect.Ptr {
+			tgtptr.Elem().Set(ret)
+		} else if tgt.CanSet() {
+			tgt.Set(ret)
+		} else {
+			err = ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&ret), ref.Typfmtv(&ret))
+		}
+	}
+	return
+}
+
+// nullValue returns the value of a sql.Null*, a pointer or a plain
+// value, and whether it's valid (not NULL).
+func (c *nullConverter) nullValue(ctx *ValueConverterContext, source reflect.Value) (val reflect.Value, valid bool) {
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return
+		}
+		source = source.Elem()
+	}
+	if i, ok := nullFields(source.Type()); ok {
+		return source.Field(i), source.Field(1 - i).Bool()
+	}
+	zeroAsNull := ctx != nil && ctx.Params != nil && ctx.controller != nil && ctx.controller.zeroAsNull
+	return source, !zeroAsNull || !source.IsZero()
+}
+
+//nolint:lll //keep it
+func (c *nullConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) {
+	val, valid := c.nullValue(ctx, source)
+	tgtType := derefType(targetType)
+
+	if i, ok := nullFields(tgtType); ok {
+		ret := reflect.New(tgtType).Elem()
+		if valid {
+			var v reflect.Value
+			if v, err = c.convertValue(ctx, val, tgtType.Field(i).Type); err != nil {
+				return
+			}
+			ret.Field(i).Set(v)
+			ret.Field(1 - i).SetBool(true)
+		}
+		target = ret
+	} else if !valid {
+		return reflect.Zero(targetType), nil // a nil pointer, or a zero value
+	} else if target, err = c.convertValue(ctx, val, tgtType); err != nil {
+		return
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		ptr := reflect.New(tgtType)
+		ptr.Elem().Set(target)
+		target = ptr
+	}
+	return
+}
+
+// convertValue converts the value of a sql.Null* to typ, or vice versa.
+func (c *nullConverter) convertValue(ctx *ValueConverterContext, val reflect.Value, typ reflect.Type) (ret reflect.Value, err error) {
+	if val.Type().AssignableTo(typ) {
+		ret = reflect.New(typ).Elem()
+		ret.Set(val)
+		return
+	}
+	if ctx == nil || ctx.Params == nil || ctx.controller == nil {
+		return convertDriverValue(nil, val, typ)
+	}
+	ret = reflect.New(typ).Elem()
+	err = ctx.controller.copyTo(ctx.Params, val, ret)
+	return
+}
+
+//nolint:lll //keep it
+func (c *nullConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
+	st, tt := derefType(source), derefType(target)
+	if st != tt && (isNullType(st) || isNullType(tt)) {
+		if yes = isNullBridgeable(st) && isNullBridgeable(tt); yes {
+			ctx = &ValueConverterContext{params}
+		}
+	}
+	return
+}
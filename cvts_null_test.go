@@ -0,0 +1,89 @@
+package evendeep_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+type dbUser struct {
+	Name    sql.NullString
+	Age     sql.NullInt64
+	Born    sql.NullTime
+	Score   sql.Null[float64]
+	Nick    sql.NullString
+	Company sql.NullString
+}
+
+type apiUser struct {
+	Name    *string
+	Age     *int
+	Born    *time.Time
+	Score   float64
+	Nick    string
+	Company *string
+}
+
+func TestNullConverters(t *testing.T) {
+	born := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+	row := dbUser{
+		Name:  sql.NullString{String: "bob", Valid: true},
+		Age:   sql.NullInt64{Int64: 42, Valid: true},
+		Born:  sql.NullTime{Time: born, Valid: true},
+		Score: sql.Null[float64]{V: 9.5, Valid: true},
+		Nick:  sql.NullString{String: "", Valid: true},
+	}
+
+	var u apiUser
+	if err := evendeep.New().CopyTo(row, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Name == nil || *u.Name != "bob" || u.Age == nil || *u.Age != 42 ||
+		u.Born == nil || !u.Born.Equal(born) || u.Score != 9.5 || u.Company != nil {
+		t.Fatalf("bad result: %+v", u)
+	}
+
+	// and back
+	var back dbUser
+	if err := evendeep.New().CopyTo(u, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back != row {
+		t.Fatalf("bad result:\n got %+v\nwant %+v", back, row)
+	}
+
+	// a zero value can be NULL
+	var nulls dbUser
+	if err := evendeep.New(evendeep.WithZeroAsNull()).CopyTo(u, &nulls); err != nil {
+		t.Fatal(err)
+	}
+	if nulls.Nick.Valid || !nulls.Name.Valid || nulls.Company.Valid {
+		t.Fatalf("bad result: %+v", nulls)
+	}
+}
+
+func TestNullConverters_Omit(t *testing.T) {
+	company := "acme"
+	u := apiUser{Company: &company}
+	row := dbUser{Company: sql.NullString{}}
+
+	// a NULL clobbers the target by default
+	if err := evendeep.New().CopyTo(row, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Company != nil {
+		t.Fatalf("bad result: %v", *u.Company)
+	}
+
+	// but not in merging with OmitIfNil
+	u.Company = &company
+	if err := evendeep.New(evendeep.WithStrategies(cms.OmitIfNil)).CopyTo(row, &u); err != nil {
+		t.Fatal(err)
+	}
+	if u.Company == nil || *u.Company != "acme" {
+		t.Fatalf("bad result: %v", u.Company)
+	}
+}
@@ -0,0 +1,273 @@
+package evendeep
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+//nolint:gochecknoglobals //i know that
+var (
+	lockerType  = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+	syncMapType = reflect.TypeOf((*sync.Map)(nil)).Elem()
+	syncCondTyp = reflect.TypeOf((*sync.Cond)(nil)).Elem()
+	syncPoolTyp = reflect.TypeOf((*sync.Pool)(nil)).Elem()
+)
+
+func isSyncPkgType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() == "sync"
+}
+
+func isAtomicType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.PkgPath() == "sync/atomic"
+}
+
+// isLockType tests if typ is a lock which should not be copied, that
+// is, a type in sync or sync/atomic package, or a user-defined lock
+// (such as a `noCopy` marker) which implements sync.Locker by itself
+// rather than an embedded field.
+func isLockType(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	if isSyncPkgType(typ) || isAtomicType(typ) {
+		return true
+	}
+	if !reflect.PointerTo(typ).Implements(lockerType) {
+		return false
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		if typ.Field(i).Anonymous {
+			return false // the Lock() might be promoted from it
+		}
+	}
+	return true
+}
+
+// LockFields returns the paths of the fields which hold a lock in the
+// struct type typ (or a pointer to it), such as ["Mutex",
+// "Stats.count", "WG"] for:
+//
+//	type Service struct {
+//	    sync.Mutex
+//	    Stats struct{ count atomic.Int64 }
+//	    WG    sync.WaitGroup
+//	    Peer  *Service // not a lock, a pointer can be copied
+//	}
+//
+// A lock is a type in sync or sync/atomic package, or a user-defined
+// lock which implements sync.Locker, such as a `noCopy` marker.
+//
+// See also WithLockReport.
+func LockFields(typ reflect.Type) (paths []string) {
+	if v, ok := lockFieldsCache.Load(typ); ok {
+		return v.([]string) //nolint:forcetypeassert //it's ours
+	}
+	paths = findLockFields(ref.RindirectType(typ), "", nil)
+	lockFieldsCache.Store(typ, paths)
+	return
+}
+
+var lockFieldsCache sync.Map //nolint:gochecknoglobals //i know that
+
+func findLockFields(typ reflect.Type, prefix string, paths []string) []string {
+	switch typ.Kind() { //nolint:exhaustive //no need
+	case reflect.Array:
+		return findLockFields(typ.Elem(), prefix+"[]", paths)
+	case reflect.Struct:
+		if isLockType(typ) {
+			return append(paths, prefix)
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			name := f.Name
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			paths = findLockFields(f.Type, name, paths)
+		}
+	}
+	return paths
+}
+
+// fromSyncPkgConverter provides the well-defined semantics for the
+// entities in sync and sync/atomic package, and the user-defined
+// locks, which should NOT be copied as is:
+//
+//   - sync.Mutex, sync.RWMutex, sync.Once, sync.WaitGroup and a
+//     user-defined lock are reset to zero,
+//   - sync.Cond is reset to zero but keeps its L,
+//   - sync.Pool is reset to zero but shares the New func,
+//   - sync.Map is cleared and filled with the deep-copied entries,
+//   - the values of atomic.Int64, atomic.Value, atomic.Pointer[T],
+//     and so on, are copied by Load and Store. They can be converted
+//     from/to a plain value too, such as atomic.Int64 to int.
+type fromSyncPkgConverter struct{ fromConverterBase }
+
+//nolint:lll //keep it
+func (c *fromSyncPkgConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
+	st, tt := derefType(source), derefType(target)
+	switch {
+	case isAtomicType(st), isAtomicType(tt):
+		yes = true
+	case st == tt:
+		yes = isLockType(st)
+	}
+	if yes {
+		ctx = &ValueConverterContext{params}
+		dbglog.Log("    src: %v, tgt: %v | Matched", source, target)
+	}
+	return
+}
+
+func (c *fromSyncPkgConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
+	tgt, tgtptr := ref.Rdecode(target)
+	if tgtptr.Kind() == reflect.Ptr {
+		if tgtptr.IsNil() {
+			if !tgtptr.CanSet() {
+				return ErrCannotSet.FormatWith(ref.Valfmt(&tgtptr), ref.Typfmtv(&tgtptr), ref.Valfmt(&source), ref.Typfmtv(&source))
+			}
+			tgtptr.Set(reflect.New(tgtptr.Type().Elem()))
+		}
+		tgt = tgtptr.Elem()
+	}
+	if !tgt.CanAddr() {
+		return ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&source), ref.Typfmtv(&source))
+	}
+
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return
+		}
+		source = source.Elem()
+	}
+	if !source.CanAddr() { // Load() needs a pointer receiver
+		tmp := reflect.New(source.Type()).Elem()
+		tmp.Set(source)
+		source = tmp
+	}
+
+	st, tt := source.Type(), tgt.Type()
+	switch {
+	case isAtomicType(st):
+		v := source.Addr().MethodByName("Load").Call(nil)[0]
+		if isAtomicType(tt) {
+			err = c.storeAtomic(ctx, v, tgt)
+		} else {
+			err = c.copyValue(ctx, v, tgt)
+		}
+	case isAtomicType(tt):
+		err = c.storeAtomic(ctx, source, tgt)
+	case st == syncMapType:
+		err = c.copySyncMap(ctx, source.Addr().Interface().(*sync.Map), tgt.Addr().Interface().(*sync.Map)) //nolint:forcetypeassert //checked
+	case st == syncCondTyp:
+		l := reflect.New(lockerType).Elem()
+		l.Set(tgt.FieldByName("L"))
+		tgt.Set(reflect.Zero(tt))
+		tgt.FieldByName("L").Set(l)
+	case st == syncPoolTyp:
+		tgt.Set(reflect.Zero(tt))
+		tgt.FieldByName("New").Set(source.FieldByName("New"))
+	default:
+		tgt.Set(reflect.Zero(tt))
+	}
+	return
+}
+
+func (c *fromSyncPkgConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) { //nolint:lll
+	ptr := reflect.New(derefType(targetType))
+	if err = c.CopyTo(ctx, source, ptr); err == nil {
+		// NOTE a lock is returned by value here, it's not locked.
+		if targetType.Kind() == reflect.Ptr {
+			target = ptr
+		} else {
+			target = ptr.Elem()
+		}
+	}
+	return
+}
+
+// storeAtomic stores v into an atomic target, v is converted to the
+// type of Store() argument if necessary.
+func (c *fromSyncPkgConverter) storeAtomic(ctx *ValueConverterContext, v, tgt reflect.Value) (err error) {
+	store := tgt.Addr().MethodByName("Store")
+	if !store.IsValid() {
+		return ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&v), ref.Typfmtv(&v))
+	}
+	at := store.Type().In(0)
+	if at.Kind() == reflect.Interface { // atomic.Value
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		if !v.IsValid() {
+			return // nothing to store, atomic.Value can't store nil
+		}
+	} else if !v.Type().AssignableTo(at) {
+		nv := reflect.New(at).Elem()
+		if err = c.copyValue(ctx, v, nv); err != nil {
+			return
+		}
+		v = nv
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&v), fmt.Sprint(e))
+		}
+	}()
+	store.Call([]reflect.Value{v})
+	return
+}
+
+// copySyncMap clears the target map, and fills it with the deep-copied
+// entries of the source map.
+func (c *fromSyncPkgConverter) copySyncMap(ctx *ValueConverterContext, from, to *sync.Map) (err error) {
+	if from == to {
+		return
+	}
+	to.Clear()
+	from.Range(func(key, value any) bool {
+		if value != nil {
+			v := reflect.ValueOf(value)
+			nv := reflect.New(v.Type()).Elem()
+			if err = c.copyValue(ctx, v, nv); err != nil {
+				return false
+			}
+			value = nv.Interface()
+		}
+		to.Store(key, value)
+		return true
+	})
+	return
+}
+
+// copyValue deep-copies v into an addressable target by the
+// controller, or sets it simply if there is no controller.
+func (c *fromSyncPkgConverter) copyValue(ctx *ValueConverterContext, v, target reflect.Value) (err error) {
+	if ctx == nil || ctx.Params == nil || ctx.controller == nil {
+		if !v.Type().ConvertibleTo(target.Type()) {
+			return ErrCannotConvertTo.FormatWith(v, ref.Typfmtv(&v), target.Type(), target.Kind())
+		}
+		target.Set(v.Convert(target.Type()))
+		return
+	}
+	cc := ctx.controller
+	return cc.copyTo(newParams(withOwners(cc, ctx.Params, &v, &target, &v, &target)), v, target)
+}
+
+// lockReport calls the report func of WithLockReport if the source
+// type holds any locks.
+func (c *cpController) lockReport(typ reflect.Type) {
+	if c.lockReporter == nil {
+		return
+	}
+	typ = ref.RindirectType(typ)
+	if paths := LockFields(typ); len(paths) > 0 {
+		dbglog.Log("    %v holds locks: %v", typ, strings.Join(paths, ", "))
+		c.lockReporter(typ, paths)
+	}
+}
@@ -0,0 +1,113 @@
+package evendeep_test
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+// spinLock is a user-defined lock.
+type spinLock struct{ State int32 }
+
+func (l *spinLock) Lock() {
+	for !atomic.CompareAndSwapInt32(&l.State, 0, 1) { //nolint:revive //spin
+	}
+}
+func (l *spinLock) Unlock() { atomic.StoreInt32(&l.State, 0) }
+
+type counters struct {
+	sync.Mutex
+	Hits  atomic.Int64
+	Name  atomic.Value
+	Peer  atomic.Pointer[string]
+	Once  sync.Once
+	Cache sync.Map
+	Spin  spinLock
+	Total int
+}
+
+func TestSyncPkgTypes(t *testing.T) {
+	peer := "peer"
+	src := &counters{Total: 3}
+	src.Lock()
+	defer src.Unlock()
+	src.Hits.Store(42)
+	src.Name.Store("svc")
+	src.Peer.Store(&peer)
+	src.Once.Do(func() {})
+	src.Cache.Store("a", []int{1, 2})
+	src.Spin.Lock()
+
+	var tgt counters
+	if err := evendeep.New().CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if !tgt.TryLock() {
+		t.Fatal("want an unlocked mutex")
+	}
+	if tgt.Spin.State != 0 {
+		t.Fatal("want an unlocked spinLock")
+	}
+	if tgt.Hits.Load() != 42 || tgt.Name.Load() != "svc" || tgt.Peer.Load() != &peer || tgt.Total != 3 {
+		t.Fatalf("bad atomic values: %v, %v, %v", tgt.Hits.Load(), tgt.Name.Load(), tgt.Peer.Load())
+	}
+	done := false
+	tgt.Once.Do(func() { done = true })
+	if !done {
+		t.Fatal("want a fresh sync.Once")
+	}
+	v, ok := tgt.Cache.Load("a")
+	if !ok || !reflect.DeepEqual(v, []int{1, 2}) {
+		t.Fatalf("bad sync.Map: %v", v)
+	}
+	sv, _ := src.Cache.Load("a")
+	sv.([]int)[0] = 9
+	if v.([]int)[0] != 1 {
+		t.Fatal("want a deep-copied sync.Map entry")
+	}
+
+	// from/to the plain values
+	var back struct {
+		Hits atomic.Int32
+		Name atomic.Value
+	}
+	if err := evendeep.New().CopyTo(struct {
+		Hits int64
+		Name string
+	}{42, "svc"}, &back); err != nil || back.Hits.Load() != 42 || back.Name.Load() != "svc" {
+		t.Fatalf("bad result: %v, %v, %v", back.Hits.Load(), back.Name.Load(), err)
+	}
+	var plain struct {
+		Hits int
+		Name string
+	}
+	if err := evendeep.New().CopyTo(&back, &plain); err != nil || plain.Hits != 42 || plain.Name != "svc" {
+		t.Fatalf("bad result: %+v, %v", plain, err)
+	}
+}
+
+func TestLockFields(t *testing.T) {
+	type service struct {
+		Counters counters
+		Peers    [2]struct{ mu sync.RWMutex }
+		Next     *service
+		Name     string
+	}
+	want := "Counters.Mutex,Counters.Hits,Counters.Name,Counters.Peer,Counters.Once,Counters.Cache,Counters.Spin,Peers[].mu"
+	if got := strings.Join(evendeep.LockFields(reflect.TypeOf(&service{})), ","); got != want {
+		t.Fatalf("bad lock fields:\n got %v\nwant %v", got, want)
+	}
+
+	var reported []string
+	var tgt service
+	err := evendeep.New(evendeep.WithLockReport(func(typ reflect.Type, paths []string) {
+		reported = append(reported, typ.Name())
+	})).CopyTo(&service{Name: "a"}, &tgt)
+	if err != nil || tgt.Name != "a" || len(reported) != 1 || reported[0] != "service" {
+		t.Fatalf("bad report: %v, %v", reported, err)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/hedzr/evendeep"
-	"github.com/hedzr/evendeep/internal/times"
+	"github.com/hedzr/evendeep/times"
 )
 
 func TestCvt_Bool(t *testing.T) {
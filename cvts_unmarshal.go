@@ -0,0 +1,191 @@
+package evendeep
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+//nolint:gochecknoglobals //i know that
+var (
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType   = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+	sqlScannerType        = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	driverValuerType      = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// isUnmarshalerType tests if *typ implements encoding.TextUnmarshaler,
+// json.Unmarshaler, encoding.BinaryUnmarshaler or sql.Scanner.
+func isUnmarshalerType(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Interface {
+		return false
+	}
+	pt := reflect.PointerTo(typ)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(jsonUnmarshalerType) ||
+		pt.Implements(binaryUnmarshalerType) || pt.Implements(sqlScannerType)
+}
+
+func isBytesType(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8
+}
+
+// unmarshalerConverter converts a string or []byte to a type which
+// implements the unmarshalers (in order: encoding.TextUnmarshaler,
+// json.Unmarshaler, encoding.BinaryUnmarshaler and sql.Scanner), and
+// converts a driver.Valuer by its Value().
+type unmarshalerConverter struct{ cvtbase }
+
+func (c *unmarshalerConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
+	tgt, tgtptr := ref.Rdecode(target)
+	tgtType := c.safeType(tgt, tgtptr) // because tgt might be invalid, so we fetch tgt type via its pointer
+	dbglog.Log("  target: %v (%v), tgtptr: %v, tgt: %v, tgttyp: %v",
+		ref.Typfmtv(&target), ref.Typfmt(target.Type()), ref.Typfmtv(&tgtptr),
+		ref.Typfmtv(&tgt), ref.Typfmt(tgtType))
+
+	var ret reflect.Value
+	if ret, err = c.Transform(ctx, source, tgtType); err == nil {
+		if k := tgtptr.Kind(); k == reflect.Interface { //nolint:gocritic // no need to switch to 'switch' clause
+			tgtptr.Set(ret)
+		} else if k == reflect.Ptr {
+			tgtptr.Elem().Set(ret)
+		} else if tgt.CanSet() {
+			tgt.Set(ret)
+		} else {
+			err = ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&ret), ref.Typfmtv(&ret))
+		}
+	}
+	return
+}
+
+//nolint:lll //keep it
+func (c *unmarshalerConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) {
+	var params *Params
+	if ctx != nil {
+		params = ctx.Params
+	}
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return reflect.Zero(targetType), nil
+		}
+		source = source.Elem()
+	}
+	tgtType := derefType(targetType)
+
+	ptr := reflect.New(tgtType)
+	if st := source.Type(); st.Kind() == reflect.String || isBytesType(st) {
+		err = unmarshalTo(ptr, source)
+	} else if valuer, ok := source.Interface().(driver.Valuer); ok {
+		var v any
+		if v, err = valuer.Value(); err != nil {
+			return
+		}
+		switch rv := reflect.ValueOf(v); {
+		case isUnmarshalerType(tgtType) && ptr.Type().Implements(sqlScannerType):
+			err = ptr.Interface().(sql.Scanner).Scan(v)
+		case rv.IsValid() && isUnmarshalerType(tgtType) && (rv.Kind() == reflect.String || isBytesType(rv.Type())):
+			err = unmarshalTo(ptr, rv)
+		default:
+			var ret reflect.Value
+			if ret, err = convertDriverValue(params, rv, tgtType); err == nil {
+				ptr.Elem().Set(ret)
+			}
+		}
+	}
+	if err != nil {
+		if _, ok := err.(*ConversionError); !ok { //nolint:errorlint //it's ours
+			err = &ConversionError{Value: source.Interface(), Target: tgtType, Err: fmt.Errorf("%w: %v", ErrBadSyntax, err)}
+		}
+		return
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		target = ptr
+	} else {
+		target = ptr.Elem()
+	}
+	return
+}
+
+//nolint:lll //keep it
+func (c *unmarshalerConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
+	st, tt := derefType(source), derefType(target)
+	if st == tt || tt.Kind() == reflect.Interface {
+		return
+	}
+	if st.Kind() == reflect.String || isBytesType(st) {
+		yes = isUnmarshalerType(tt)
+	} else if source.Implements(driverValuerType) {
+		yes = tt.Kind() != reflect.Struct || isUnmarshalerType(tt)
+	}
+	if yes {
+		ctx = &ValueConverterContext{params}
+	}
+	return
+}
+
+// unmarshalTo unmarshals a string or []byte source into ptr.
+//
+// A string which isn't a valid JSON is quoted for json.Unmarshaler,
+// so that "red" can be unmarshalled as `"red"`.
+func unmarshalTo(ptr, source reflect.Value) (err error) {
+	var data []byte
+	var raw any // for sql.Scanner
+	if source.Kind() == reflect.String {
+		raw = source.String()
+		data = []byte(source.String())
+	} else {
+		data = source.Bytes()
+		raw = data
+	}
+
+	switch u := ptr.Interface().(type) {
+	case encoding.TextUnmarshaler:
+		err = u.UnmarshalText(data)
+	case json.Unmarshaler:
+		if json.Valid(data) {
+			if err = u.UnmarshalJSON(data); err == nil {
+				return
+			}
+		}
+		quoted, _ := json.Marshal(string(data)) //nolint:errchkjson //a string is always ok
+		if e := u.UnmarshalJSON(quoted); e == nil || err == nil {
+			err = e
+		}
+	case encoding.BinaryUnmarshaler:
+		err = u.UnmarshalBinary(data)
+	case sql.Scanner:
+		err = u.Scan(raw)
+	}
+	return
+}
+
+// convertDriverValue converts a driver.Value (int64, float64, bool,
+// []byte, string, time.Time, or nil) to typ.
+func convertDriverValue(params *Params, rv reflect.Value, typ reflect.Type) (target reflect.Value, err error) {
+	switch {
+	case !rv.IsValid():
+		target = reflect.Zero(typ)
+	case rv.Kind() == typ.Kind() && rv.Type().ConvertibleTo(typ):
+		target = rv.Convert(typ)
+	case isNumberKind(rv.Kind()) && isNumberKind(typ.Kind()):
+		target, err = convertNumber(rv, typ, params.numConvMode())
+	case rv.Kind() == reflect.String:
+		target, err = (&fromStringConverter{}).Transform(&ValueConverterContext{params}, rv, typ)
+	case typ.Kind() == reflect.String:
+		if target, err = rToString(rv, typ); err == nil {
+			target = target.Convert(typ)
+		}
+	case rv.Type().ConvertibleTo(typ):
+		target = rv.Convert(typ)
+	default:
+		err = ErrCannotConvertTo.FormatWith(rv, ref.Typfmtv(&rv), typ, typ.Kind())
+	}
+	return
+}
@@ -0,0 +1,130 @@
+package evendeep_test
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+type color int
+
+func (c color) MarshalText() ([]byte, error) {
+	return []byte([]string{"red", "green", "blue"}[c]), nil
+}
+
+func (c *color) UnmarshalText(b []byte) error {
+	for i, s := range []string{"red", "green", "blue"} {
+		if s == string(b) {
+			*c = color(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown color %q", b)
+}
+
+// money only implements json.Unmarshaler, both of 12.34 and "12.34"
+// are accepted.
+type money struct{ cents int64 }
+
+func (m *money) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		s = string(b)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	m.cents = int64(f*100 + 0.5)
+	return err
+}
+
+type version struct{ major, minor uint16 }
+
+func (v *version) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return fmt.Errorf("bad version length %d", len(b))
+	}
+	v.major, v.minor = binary.BigEndian.Uint16(b), binary.BigEndian.Uint16(b[2:])
+	return nil
+}
+
+func TestUnmarshalers(t *testing.T) {
+	type target struct {
+		Color   color
+		Price   money
+		Version version
+		Name    sql.NullString
+		Age     int
+		Score   sql.NullInt64
+	}
+	type source struct {
+		Color   string
+		Price   string
+		Version []byte
+		Name    string
+		Age     sql.NullInt64
+		Score   sql.NullString
+	}
+
+	src := source{
+		Color:   "green",
+		Price:   "12.34",
+		Version: []byte{0, 1, 0, 2},
+		Name:    "bob",
+		Age:     sql.NullInt64{Int64: 42, Valid: true},
+		Score:   sql.NullString{String: "99", Valid: true},
+	}
+	var tgt target
+	if err := evendeep.New().CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	want := target{
+		Color:   1,
+		Price:   money{1234},
+		Version: version{1, 2},
+		Name:    sql.NullString{String: "bob", Valid: true},
+		Age:     42,
+		Score:   sql.NullInt64{Int64: 99, Valid: true},
+	}
+	if tgt != want {
+		t.Fatalf("bad result:\n got %+v\nwant %+v", tgt, want)
+	}
+
+	var clone target
+	if err := evendeep.New().CopyTo(&tgt, &clone); err != nil || clone != want {
+		t.Fatalf("bad clone: %+v, %v", clone, err)
+	}
+
+	// a map source, and the json number
+	var tgt2 target
+	if err := evendeep.New().CopyTo(map[string]any{"Color": "blue", "Price": []byte("5")}, &tgt2); err != nil {
+		t.Fatal(err)
+	}
+	if tgt2.Color != 2 || tgt2.Price.cents != 500 {
+		t.Fatalf("bad result: %+v", tgt2)
+	}
+
+	// and back to string by MarshalText
+	var out struct{ Color string }
+	if err := evendeep.New().CopyTo(struct{ Color color }{tgt.Color}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Color != "green" {
+		t.Fatalf("bad result: %+v", out)
+	}
+}
+
+func TestUnmarshalers_Error(t *testing.T) {
+	var tgt struct{ Color color }
+	err := evendeep.New().CopyTo(map[string]any{"Color": "pink"}, &tgt)
+	var ce *evendeep.ConversionError
+	if !errors.Is(err, evendeep.ErrBadSyntax) || !errors.As(err, &ce) {
+		t.Fatalf("want ErrBadSyntax, got %v", err)
+	}
+	t.Log(err)
+}
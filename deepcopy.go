@@ -60,6 +60,12 @@ func DeepCopy(fromObj, toObj interface{}, opts ...Opt) (result interface{}) { //
 }
 
 // MakeClone makes a deep clone of a source object.
+//
+// Since v1.4.1 the shared references and the cycles in the source are
+// mapped 1:1 in the clone (see WithPreserveAliasing), so two fields
+// pointing to the same object point to the same new object, rather
+// than two copies. Use New(WithPreserveAliasing(false)).CopyTo() for
+// a copy per reference.
 func MakeClone(fromObj interface{}) (result interface{}) { //nolint:revive
 	if fromObj == nil {
 		return nil
@@ -172,8 +178,9 @@ func newCloner() *cpController {
 		autoExpandStruct:           true,
 		autoNewStruct:              true,
 
-		flags:        flags.New(cms.Default),
-		rethrow:      true,
-		makeNewClone: true,
+		flags:            flags.New(cms.Default),
+		rethrow:          true,
+		makeNewClone:     true,
+		preserveAliasing: true,
 	}
 }
@@ -0,0 +1,65 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"github.com/hedzr/evendeep/diff"
+	"github.com/hedzr/evendeep/ref"
+	"github.com/hedzr/evendeep/typ"
+)
+
+// DryRunResult holds the outcome of a dry-run copying.
+//
+// See WithDryRun.
+type DryRunResult struct {
+	// Result is a pointer to a clone of the target with the source
+	// copied/merged into it.
+	Result typ.Any
+	// Changes records the differences between the untouched target
+	// and Result.
+	Changes diff.Diff
+	// Equal is true if the copying changes nothing.
+	Equal bool
+}
+
+// WithDryRun makes CopyTo report what a copying/merging would do to
+// the target without touching it.
+//
+// The target will be cloned at first, and the source be copied to
+// the clone. The clone and the changes are returned in result.
+//
+// For instance:
+//
+//	var result evendeep.DryRunResult
+//	err := evendeep.New().CopyTo(newLayer, &cfg, evendeep.WithDryRun(&result))
+//	fmt.Println(result.Changes)  // preview the effect
+//	newCfg := result.Result.(*Config)
+//
+// WithDryRun is effective on the next CopyTo only. A nil target can't
+// be previewed, and CopyTo returns ErrCannotSet for it.
+func WithDryRun(result *DryRunResult) Opt {
+	return func(c *cpController) {
+		c.dryRun = result
+	}
+}
+
+func (c *cpController) dryRunTo(result *DryRunResult, fromObjOrPtr, toObjPtr interface{}) (err error) { //nolint:revive
+	to := reflect.ValueOf(toObjPtr)
+	tgt := ref.Rindirect(to)
+	if !tgt.IsValid() {
+		from := reflect.ValueOf(fromObjOrPtr)
+		return ErrCannotSet.FormatWith(ref.Valfmt(&from), ref.Typfmtv(&from), ref.Valfmt(&to), ref.Typfmtv(&to))
+	}
+
+	clone := reflect.New(tgt.Type())
+	if err = newCloner().CopyTo(tgt.Interface(), clone.Interface()); err != nil {
+		return
+	}
+	if err = c.CopyTo(fromObjOrPtr, clone.Interface()); err != nil {
+		return
+	}
+
+	result.Result = clone.Interface()
+	result.Changes, result.Equal = diff.New(tgt.Interface(), clone.Elem().Interface())
+	return
+}
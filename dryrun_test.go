@@ -0,0 +1,71 @@
+package evendeep_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestWithDryRun(t *testing.T) {
+	type Server struct {
+		Host  string
+		Port  int
+		Tags  []string
+		Extra map[string]string
+	}
+	type Config struct {
+		Name   string
+		Server *Server
+	}
+
+	cfg := Config{
+		Name:   "app",
+		Server: &Server{Host: "localhost", Port: 80, Tags: []string{"a"}, Extra: map[string]string{"k": "v"}},
+	}
+	layer := Config{
+		Name:   "app",
+		Server: &Server{Port: 8080, Tags: []string{"b"}, Extra: map[string]string{"k2": "v2"}},
+	}
+
+	var result evendeep.DryRunResult
+	c := evendeep.New()
+	err := c.CopyTo(&layer, &cfg, evendeep.WithDryRun(&result), evendeep.WithOmitEmptyOpt)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	t.Logf("changes:\n%v", result.Changes)
+
+	if cfg.Server.Port != 80 || len(cfg.Server.Tags) != 1 || len(cfg.Server.Extra) != 1 {
+		t.Fatalf("target should not be touched: %+v", cfg.Server)
+	}
+	preview, ok := result.Result.(*Config)
+	if !ok {
+		t.Fatalf("bad result type: %T", result.Result)
+	}
+	if preview.Server.Port != 8080 || preview.Server.Host != "localhost" {
+		t.Fatalf("bad preview: %+v", preview.Server)
+	}
+	if result.Equal || result.Changes == nil {
+		t.Fatalf("expecting changes")
+	}
+
+	// the dry-run option is one-shot
+	if err = c.CopyTo(&layer, &cfg); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if cfg.Server.Port != 8080 {
+		t.Fatalf("target should be merged now: %+v", cfg.Server)
+	}
+}
+
+func TestWithDryRun_nilTarget(t *testing.T) {
+	type Config struct{ Name string }
+	var result evendeep.DryRunResult
+	for _, tgt := range []any{nil, (*Config)(nil)} {
+		err := evendeep.New().CopyTo(&Config{Name: "x"}, tgt, evendeep.WithDryRun(&result))
+		if !errors.Is(err, evendeep.ErrCannotSet) {
+			t.Fatalf("want ErrCannotSet for %v, got %v", tgt, err)
+		}
+	}
+}
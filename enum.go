@@ -0,0 +1,258 @@
+package evendeep
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// RegisterEnum registers the names of the enum type E, which is an
+// integer or string type, such as:
+//
+//	type Status int
+//	const (
+//	    Inactive Status = iota
+//	    Active
+//	)
+//
+//	evendeep.RegisterEnum(map[string]Status{"Inactive": Inactive, "Active": Active})
+//
+// Then a string source "Active" (or "active") is converted to Active,
+// Active is converted to "Active", and Active is converted to another
+// enum type by its name, such as to a `UserStatus` which has an
+// "Active" constant too.
+//
+// A value can have several names, its label is the fmt.Stringer of E
+// if E implements it, or else the first one of the sorted names.
+//
+// An integer type which has a `Parse(string) E` or a `Parse(string)
+// (E, error)` method (like cms.CopyMergeStrategy) and implements
+// fmt.Stringer is discovered as an enum type automatically, it needs
+// no registering.
+func RegisterEnum[E any](names map[string]E) {
+	typ := reflect.TypeOf((*E)(nil)).Elem()
+	if k := typ.Kind(); !isIntKind(k) && !isUintKind(k) && k != reflect.String {
+		panic(fmt.Sprintf("RegisterEnum: %v is not an integer or string type", typ))
+	}
+
+	info := &enumInfo{
+		names:  make(map[string]reflect.Value, len(names)),
+		labels: make(map[any]string, len(names)),
+	}
+	keys := make([]string, 0, len(names))
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		v := reflect.ValueOf(names[name])
+		info.names[name] = v
+		if _, ok := info.labels[v.Interface()]; !ok {
+			info.labels[v.Interface()] = name
+		}
+	}
+
+	enumsLock.Lock()
+	defer enumsLock.Unlock()
+	enums[typ] = info
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type enumInfo struct {
+	names  map[string]reflect.Value // name -> value
+	labels map[any]string           // value -> the first name
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	enums     = make(map[reflect.Type]*enumInfo)
+	enumsLock sync.RWMutex
+)
+
+func lookupEnum(typ reflect.Type) *enumInfo {
+	enumsLock.RLock()
+	defer enumsLock.RUnlock()
+	return enums[typ]
+}
+
+// enumParser returns the Parse method of an integer type, and whether
+// it returns an error too.
+func enumParser(typ reflect.Type) (mtd reflect.Method, withErr, ok bool) {
+	if k := typ.Kind(); !isIntKind(k) && !isUintKind(k) || !typ.Implements(stringerType) {
+		return
+	}
+	if mtd, ok = typ.MethodByName("Parse"); !ok {
+		return
+	}
+	mt := mtd.Type // the receiver is the first argument
+	if mt.NumIn() != 2 || mt.In(1).Kind() != reflect.String || mt.NumOut() < 1 || mt.Out(0) != typ {
+		return mtd, false, false
+	}
+	switch mt.NumOut() {
+	case 1:
+	case 2: //nolint:gomnd //(E, error)
+		withErr = mt.Out(1) == errorType
+		ok = withErr
+	default:
+		ok = false
+	}
+	return
+}
+
+// isEnumType tests if typ is registered by RegisterEnum, or has a
+// Parse method.
+func isEnumType(typ reflect.Type) bool {
+	if lookupEnum(typ) != nil {
+		return true
+	}
+	_, _, ok := enumParser(typ)
+	return ok
+}
+
+// enumLabel returns the name of an enum value v.
+func enumLabel(v reflect.Value) (label string, ok bool) {
+	if v.Type().Implements(stringerType) && v.CanInterface() {
+		return v.Interface().(fmt.Stringer).String(), true //nolint:forcetypeassert //checked
+	}
+	if info := lookupEnum(v.Type()); info != nil && v.CanInterface() {
+		label, ok = info.labels[v.Interface()]
+	}
+	return
+}
+
+// parseEnum returns the value of typ by its name, the name is
+// case-insensitive.
+func parseEnum(typ reflect.Type, name string) (v reflect.Value, ok bool) {
+	if info := lookupEnum(typ); info != nil {
+		if v, ok = info.names[name]; ok {
+			return
+		}
+		for n, val := range info.names {
+			if strings.EqualFold(n, name) {
+				return val, true
+			}
+		}
+		return
+	}
+
+	if mtd, withErr, found := enumParser(typ); found {
+		out := mtd.Func.Call([]reflect.Value{reflect.Zero(typ), reflect.ValueOf(name).Convert(mtd.Type.In(1))})
+		if withErr {
+			return out[0], out[1].IsNil()
+		}
+		// a Parse(string) E returns an invalid value rather than an
+		// error, so we check it by its name.
+		if label, _ := enumLabel(out[0]); strings.EqualFold(label, name) {
+			return out[0], true
+		}
+	}
+	return
+}
+
+// enumConverter converts a string to an enum type by its name (see
+// RegisterEnum), an enum to string by its label, and an enum to
+// another enum type by its label.
+type enumConverter struct{ cvtbase }
+
+func (c *enumConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
+	tgt, tgtptr := ref.Rdecode(target)
+	tgtType := c.safeType(tgt, tgtptr) // because tgt might be invalid, so we fetch tgt type via its pointer
+	dbglog.Log("  target: %v (%v), tgtptr: %v, tgt: %v, tgttyp: %v",
+		ref.Typfmtv(&target), ref.Typfmt(target.Type()), ref.Typfmtv(&tgtptr),
+		ref.Typfmtv(&tgt), ref.Typfmt(tgtType))
+
+	var ret reflect.Value
+	if ret, err = c.Transform(ctx, source, tgtType); err == nil {
+		if k := tgtptr.Kind(); k == reflect.Interface { //nolint:gocritic // no need to switch to 'switch' clause
+			tgtptr.Set(ret)
+		} else if k == reflect.Ptr {
+			tgtptr.Elem().Set(ret)
+		} else if tgt.CanSet() {
+			tgt.Set(ret)
+		} else {
+			err = ErrCannotSet.FormatWith(ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Valfmt(&ret), ref.Typfmtv(&ret))
+		}
+	}
+	return
+}
+
+//nolint:lll //keep it
+func (c *enumConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) {
+	var params *Params
+	if ctx != nil {
+		params = ctx.Params
+	}
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return reflect.Zero(targetType), nil
+		}
+		source = source.Elem()
+	}
+	tgtType := derefType(targetType)
+
+	name, labelled := enumLabel(source)
+	if !labelled && source.Kind() == reflect.String {
+		name, labelled = source.String(), true
+	}
+
+	var ret reflect.Value
+	var ok bool
+	switch {
+	case tgtType.Kind() == reflect.String && !isEnumType(tgtType):
+		if !labelled {
+			name = fmt.Sprint(source.Interface()) // such as Status(99)
+		}
+		ret = reflect.ValueOf(name).Convert(tgtType)
+	case !labelled && isNumberKind(source.Kind()):
+		ret, err = convertNumber(source, tgtType, params.numConvMode())
+	default:
+		if ret, ok = parseEnum(tgtType, name); ok {
+			break
+		}
+		if source.Kind() == reflect.String && tgtType.Kind() != reflect.String {
+			if ret, err = parseNumber(name, tgtType, numConvStrict); err == nil {
+				break // such as "1"
+			}
+		}
+		err = &ConversionError{Value: source.Interface(), Target: tgtType, Err: fmt.Errorf("%w: unknown name %q", ErrBadSyntax, name)}
+	}
+	if err != nil {
+		return
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		target = reflect.New(tgtType)
+		target.Elem().Set(ret)
+	} else {
+		target = ret
+	}
+	return
+}
+
+//nolint:lll //keep it
+func (c *enumConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
+	st, tt := derefType(source), derefType(target)
+	if st == tt {
+		return
+	}
+	switch {
+	case isEnumType(tt):
+		yes = st.Kind() == reflect.String || isEnumType(st)
+	case tt.Kind() == reflect.String:
+		yes = isEnumType(st)
+	}
+	if yes {
+		ctx = &ValueConverterContext{params}
+	}
+	return
+}
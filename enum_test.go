@@ -0,0 +1,123 @@
+package evendeep_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+type status int
+
+const (
+	inactive status = iota
+	active
+	banned
+)
+
+// userStatus has the same labels as status, but different values.
+type userStatus uint8
+
+const (
+	userActive userStatus = iota + 1
+	userInactive
+	userBanned
+)
+
+func (s userStatus) String() string {
+	switch s {
+	case userActive:
+		return "Active"
+	case userInactive:
+		return "Inactive"
+	case userBanned:
+		return "Banned"
+	}
+	return fmt.Sprintf("userStatus(%d)", s)
+}
+
+func (s userStatus) Parse(str string) (userStatus, error) {
+	for _, v := range []userStatus{userActive, userInactive, userBanned} {
+		if strings.EqualFold(v.String(), str) {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown user status %q", str)
+}
+
+func init() {
+	evendeep.RegisterEnum(map[string]status{"Inactive": inactive, "Active": active, "Banned": banned, "Blocked": banned})
+}
+
+func TestRegisterEnum(t *testing.T) {
+	type account struct {
+		Status   status
+		User     userStatus
+		Strategy cms.CopyMergeStrategy
+		Level    status
+	}
+	var tgt account
+	err := evendeep.New().CopyTo(map[string]any{
+		"Status":   "blocked",
+		"User":     "inactive",
+		"Strategy": "slicemerge",
+		"Level":    "1",
+	}, &tgt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (account{banned, userInactive, cms.SliceMerge, active}); tgt != want {
+		t.Fatalf("bad result:\n got %+v\nwant %+v", tgt, want)
+	}
+
+	// to strings, by the first sorted name or String()
+	var out struct{ Status, User, Strategy string }
+	if err = evendeep.New().CopyTo(tgt, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Status != "Banned" || out.User != "Inactive" || out.Strategy != "slicemerge" {
+		t.Fatalf("bad result: %+v", out)
+	}
+
+	// between two enum types by labels
+	var swapped struct {
+		Status userStatus
+		User   status
+	}
+	if err = evendeep.New().CopyTo(struct {
+		Status status
+		User   userStatus
+	}{active, userBanned}, &swapped); err != nil {
+		t.Fatal(err)
+	}
+	if swapped.Status != userActive || swapped.User != banned {
+		t.Fatalf("bad result: %+v", swapped)
+	}
+
+	// a value without name is converted numerically
+	if err = evendeep.New().CopyTo(struct{ Status status }{status(7)}, &swapped); err != nil || swapped.Status != 7 {
+		t.Fatalf("bad result: %+v, %v", swapped, err)
+	}
+}
+
+func TestRegisterEnum_Error(t *testing.T) {
+	for _, src := range []map[string]any{
+		{"Status": "Deleted"},
+		{"User": "Deleted"},
+	} {
+		var tgt struct {
+			Status status
+			User   userStatus
+		}
+		err := evendeep.New().CopyTo(src, &tgt)
+		var ce *evendeep.ConversionError
+		if !errors.Is(err, evendeep.ErrBadSyntax) || !errors.As(err, &ce) {
+			t.Fatalf("want ErrBadSyntax for %v, got %v", src, err)
+		}
+		t.Log(err)
+	}
+}
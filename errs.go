@@ -15,6 +15,54 @@ var (
 	// ErrCannotConvertTo error.
 	ErrCannotConvertTo = errors.New("cannot convert/set: %v (%v) -> %v (%v)")
 
+	// ErrUnknownInterfaceImpl error, the discriminator value in
+	// source is not registered by RegisterInterfaceImpl.
+	ErrUnknownInterfaceImpl = errors.New("cannot resolve the concrete type of %v: unknown %v %q")
+
+	// ErrOverflow error, the value is out of the range of the
+	// target type. See also ConversionError.
+	ErrOverflow = errors.New("value out of range")
+
+	// ErrTruncated error, the fractional part of a float would be
+	// dropped.
+	ErrTruncated = errors.New("fractional part truncated")
+
+	// ErrPrecisionLost error, an integer cannot be represented
+	// exactly by the target float type.
+	ErrPrecisionLost = errors.New("precision lost")
+
+	// ErrNegativeToUnsigned error, a negative value cannot be
+	// converted to an unsigned type.
+	ErrNegativeToUnsigned = errors.New("negative value to unsigned")
+
+	// ErrBadSyntax error, a string isn't a valid number or value.
+	ErrBadSyntax = errors.New("invalid syntax")
+
+	// ErrKeyCollision error, two source keys are mapped to the same
+	// target key in copying a map. See also WithKeyCollision.
+	ErrKeyCollision = errors.New("map key collision: %v (%v) and %v (%v) -> %v")
+
+	// ErrLengthMismatch error, the lengths of source and target are
+	// different with LenStrict policy. See also WithLengthPolicy.
+	ErrLengthMismatch = errors.New("length mismatch: %v (len %v) -> %v (len %v)")
+
+	// ErrCannotAdaptFunc error, a function can't be adapted to the
+	// target function type. See also WithFuncAdapters.
+	ErrCannotAdaptFunc = errors.New("cannot adapt function %v to %v")
+
+	// ErrChanRefill error, the source channel was filled by others
+	// in taking its snapshot, so that some of its elements cannot be
+	// put back. See also ChanSnapshot.
+	ErrChanRefill = errors.New("cannot refill the source channel (%v): %v of %v elements are in the snapshot only")
+
+	// ErrNoDedupKey error, a slice element has no field or map key
+	// named by the `dedupkey=` tag. See also cms.SliceDedup.
+	ErrNoDedupKey = errors.New("no dedup key %q in the slice element %v (%v)")
+
+	// ErrUnknownName error, a name given by an option or a tag, such
+	// as `parse=` or `mapmerge=`, isn't registered.
+	ErrUnknownName = errors.New("unknown %v %q")
+
 	// ErrShouldFallback tells the caller please continue its
 	// internal process.
 	// The error would be used in your callback function. For
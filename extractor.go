@@ -0,0 +1,124 @@
+package evendeep
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// SourcePathExtractor provides a hook for extracting the value of a
+// target struct field from an arbitrary source, such as a
+// context.Context, the environment variables, the HTTP headers or a
+// flag set.
+//
+// The path is the names of the target field and its parents, such as
+// ["DB", "Host"] for `cfg.DB.Host`. The extractor is invoked for a
+// nested struct field at first, it can return the whole struct, or
+// return ok = false to go into its fields. A leaf field is untouched
+// if ok is false.
+//
+// The value is converted to the type of target field if necessary,
+// for instance, "8080" to an int field. A nil value sets the field to
+// zero.
+type SourcePathExtractor func(path []string, targetField reflect.StructField) (value any, ok bool)
+
+// copyFromPathExtractor fills the fields of the target struct by
+// c.pathExtractor, at every nesting level.
+//
+// If c.targetSetter is set too, it receives the extracted values with
+// the reversal path, as it does for the source fields. It can return
+// ErrShouldFallback to set the value in the standard way.
+//
+// visiting holds the struct types on the path, a nil pointer to one of
+// them isn't newed to go into, so a self-referential struct such as a
+// linked list node doesn't recurse forever.
+func (c *cpController) copyFromPathExtractor(params *Params, path []string, visiting []reflect.Type, to reflect.Value) (err error) {
+	ec := errors.New("copyFromPathExtractor errors")
+	defer ec.Defer(&err)
+
+	typ := to.Type()
+	visiting = append(visiting[:len(visiting):len(visiting)], typ)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() || c.isIgnoredName(sf.Name) {
+			continue
+		}
+		if _, ignored := params.parseFieldTags(sf.Tag); ignored {
+			continue
+		}
+
+		fp, fv := append(path[:len(path):len(path)], sf.Name), to.Field(i) //nolint:gocritic //a new slice for each field
+		if v, ok := c.pathExtractor(fp, sf); ok {
+			dbglog.Log("  extracted %v: %v", fp, v)
+			c.tracer.add(params, strings.Join(fp, "."), strings.Join(fp, "."), "extractor", TraceCopied, "")
+			if e := c.setExtractedValue(params, fp, reflect.ValueOf(v), fv); e != nil {
+				c.tracer.fail(e)
+				ec.Attach(e)
+			} else {
+				c.tracer.result(fv)
+			}
+			continue
+		}
+
+		ft := ref.RindirectType(sf.Type)
+		if ft.Kind() != reflect.Struct || packageisreserved(ft.PkgPath()) || c.isKeptStructType(ft) {
+			continue
+		}
+		if sf.Type.Kind() != reflect.Ptr {
+			ec.Attach(c.copyFromPathExtractor(params, fp, visiting, fv))
+			continue
+		}
+		if !fv.IsNil() {
+			ec.Attach(c.copyFromPathExtractor(params, fp, visiting, fv.Elem()))
+			continue
+		}
+		if slices.Contains(visiting, ft) {
+			continue
+		}
+		nv := reflect.New(ft) // the nil pointer is newed only if something extracted
+		if e := c.copyFromPathExtractor(params, fp, visiting, nv.Elem()); e != nil {
+			ec.Attach(e)
+		} else if !nv.Elem().IsZero() {
+			fv.Set(nv)
+		}
+	}
+	return
+}
+
+func (c *cpController) setExtractedValue(params *Params, path []string, value, field reflect.Value) (err error) {
+	if c.targetSetter != nil {
+		names := make([]string, len(path))
+		for i, n := range path {
+			names[len(path)-1-i] = n
+		}
+		if err = c.targetSetter(&value, names...); err != ErrShouldFallback { //nolint:errorlint //want it exactly
+			return // done, or failed
+		}
+	}
+
+	if !value.IsValid() {
+		setToZero(&field)
+		return nil
+	}
+	if field.Kind() == reflect.Ptr && value.Kind() != reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+		field = field.Elem()
+	}
+	return c.copyTo(newParams(withOwners(c, params, &value, &field, &value, &field)), value, field)
+}
+
+func (c *cpController) isIgnoredName(name string) bool {
+	for _, x := range c.ignoreNames {
+		if isWildMatch(name, x) {
+			return true
+		}
+	}
+	return false
+}
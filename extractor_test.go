@@ -0,0 +1,139 @@
+package evendeep_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hedzr/evendeep"
+)
+
+type extractedConfig struct {
+	Name string
+	DB   struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	}
+	Cache   *struct{ Size int }
+	Logger  *struct{ Level string }
+	Ignored string `copy:"-"`
+	Kept    string
+}
+
+func TestWithSourcePathExtractor(t *testing.T) {
+	env := map[string]string{
+		"NAME":       "svc",
+		"DB_HOST":    "localhost",
+		"DB_PORT":    "5432",
+		"DB_TIMEOUT": "1m30s",
+		"CACHE_SIZE": "64",
+		"IGNORED":    "oops",
+	}
+	var paths []string
+	extractor := func(path []string, f reflect.StructField) (any, bool) {
+		key := strings.ToUpper(strings.Join(path, "_"))
+		paths = append(paths, key)
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg := extractedConfig{Kept: "kept"}
+	if err := evendeep.New().CopyTo(struct{}{}, &cfg, evendeep.WithSourcePathExtractor(extractor)); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case cfg.Name != "svc", cfg.DB.Host != "localhost", cfg.DB.Port != 5432, cfg.DB.Timeout != 90*time.Second,
+		cfg.Cache == nil || cfg.Cache.Size != 64, cfg.Logger != nil, cfg.Ignored != "", cfg.Kept != "kept":
+		t.Fatalf("bad result: %+v", cfg)
+	}
+	// a nested struct is asked as a whole at first
+	if want := "NAME,DB,DB_HOST,DB_PORT,DB_TIMEOUT,CACHE,CACHE_SIZE,LOGGER,LOGGER_LEVEL,KEPT"; strings.Join(paths, ",") != want {
+		t.Fatalf("bad paths:\n got %v\nwant %v", strings.Join(paths, ","), want)
+	}
+
+	// the whole nested struct
+	var cfg2 extractedConfig
+	err := evendeep.New().CopyTo(struct{}{}, &cfg2, evendeep.WithSourcePathExtractor(func(path []string, f reflect.StructField) (any, bool) {
+		if len(path) == 1 && path[0] == "Cache" {
+			return map[string]any{"Size": 8}, true
+		}
+		return nil, false
+	}))
+	if err != nil || cfg2.Cache == nil || cfg2.Cache.Size != 8 {
+		t.Fatalf("bad result: %+v, %v", cfg2, err)
+	}
+}
+
+func TestWithSourcePathExtractor_TargetSetter(t *testing.T) {
+	var names []string
+	var cfg extractedConfig
+	err := evendeep.New().CopyTo(struct{}{}, &cfg,
+		evendeep.WithSourcePathExtractor(func(path []string, f reflect.StructField) (any, bool) {
+			if f.Type.Kind() == reflect.String {
+				return strings.Join(path, "."), true
+			}
+			return nil, false
+		}),
+		evendeep.WithTargetValueSetter(func(value *reflect.Value, sourceNames ...string) (err error) {
+			names = append(names, strings.Join(sourceNames, "<"))
+			if sourceNames[0] == "Host" {
+				return evendeep.ErrShouldFallback
+			}
+			return nil // handled, nothing to set
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Host != "DB.Host" || cfg.Name != "" || cfg.Kept != "" {
+		t.Fatalf("bad result: %+v", cfg)
+	}
+	if got := strings.Join(names, ","); got != "Name,Host<DB,Level<Logger,Kept" {
+		t.Fatalf("bad names: %v", got)
+	}
+
+	// an error breaks the copying
+	errStop := errors.New("stop")
+	err = evendeep.New().CopyTo(struct{}{}, &cfg,
+		evendeep.WithSourcePathExtractor(func(path []string, f reflect.StructField) (any, bool) { return "x", true }),
+		evendeep.WithTargetValueSetter(func(value *reflect.Value, sourceNames ...string) error { return errStop }),
+	)
+	if !errors.Is(err, errStop) {
+		t.Fatalf("want errStop, got %v", err)
+	}
+}
+
+func TestWithSourcePathExtractor_selfReferential(t *testing.T) {
+	type Node struct {
+		Name string
+		Next *Node
+	}
+	var paths []string
+	ex := func(path []string, f reflect.StructField) (any, bool) {
+		paths = append(paths, strings.Join(path, "."))
+		if f.Name == "Name" {
+			return strings.Join(path, "."), true
+		}
+		return nil, false
+	}
+
+	var n Node
+	if err := evendeep.New(evendeep.WithSourcePathExtractor(ex)).CopyTo(struct{}{}, &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Name != "Name" || n.Next != nil {
+		t.Fatalf("bad result: %+v", n)
+	}
+
+	// an existing node is filled
+	paths, n = nil, Node{Next: &Node{}}
+	if err := evendeep.New(evendeep.WithSourcePathExtractor(ex)).CopyTo(struct{}{}, &n); err != nil {
+		t.Fatal(err)
+	}
+	if n.Next == nil || n.Next.Name != "Next.Name" || n.Next.Next != nil {
+		t.Fatalf("bad result: %+v, %v", n, paths)
+	}
+}
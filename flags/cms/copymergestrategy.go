@@ -79,15 +79,51 @@ const (
 	//
 	// The uniqueness checking is only applied to each source slice items.
 	SliceMerge // slicemerge
+	// SliceUnion the target and source elements will be copied to
+	// the new target with uniqueness, in their original order. Unlike
+	// SliceMerge, the source elements are compared after converted to
+	// the element type of target.
+	SliceUnion // sliceunion
+	// SliceDedup the target and source elements will be copied to the
+	// new target with the unique keys, a source element replaces the
+	// one with the same key in place. The key is the field specified
+	// by tag `copy:",slicededup,dedupkey=ID"`, or by a key func of
+	// WithSliceDedupKey, or the element itself.
+	SliceDedup // slicededup
+	// SlicePrepend the source elements will be inserted before the
+	// target ones.
+	SlicePrepend // sliceprepend
+	// SliceMergeByIndex the source element will be merged into the
+	// target element at the same index recursively, the extra source
+	// elements are appended.
+	SliceMergeByIndex // slicemergeindex
+	// SliceReplace the source elements will replace the target ones
+	// at the same index, but the target length is kept. That is, the
+	// extra source elements are dropped, and the extra target ones
+	// are kept.
+	SliceReplace // slicereplace
 
 	// MapCopy do copy source map to the target.
-	MapCopy CopyMergeStrategy = iota + 70 - 16 // mapcopy
+	MapCopy CopyMergeStrategy = iota + 70 - 21 // mapcopy
 	// MapMerge try to merge each fields inside source map recursively,
 	// even if it's a slice, a pointer, another sub-map, and so on.
 	MapMerge // mapmerge
 
 	// Flat copy a pointer instead of its object pointed.
-	Flat CopyMergeStrategy = iota + 80 - 17 // flat
+	Flat CopyMergeStrategy = iota + 80 - 22 // flat
+
+	// Wrap wraps a numeric value around as Go does in a narrowing
+	// conversion. It's the implicit default of Wrap, Strict and
+	// Saturate, so it's set only if given explicitly, such as by tag
+	// `copy:",wrap"` to override WithStrictConversions for a field.
+	Wrap CopyMergeStrategy = iota + 85 - 25 // wrap
+	// Strict reports an error for a lossy numeric conversion, such as
+	// an overflow, a truncated fraction, or a negative value to an
+	// unsigned target.
+	Strict // strict
+	// Saturate clamps a numeric value to the range of its target type
+	// rather than wrapping around.
+	Saturate // saturate
 
 	//
 	// // --- Globally settings ---.
@@ -97,7 +133,7 @@ const (
 	// All of them should NOT be used in your user-side codes.
 
 	// UnexportedToo _.
-	UnexportedToo CopyMergeStrategy = iota + 90 - 18 // private
+	UnexportedToo CopyMergeStrategy = iota + 90 - 26 // private
 
 	// ByOrdinal will be applied to struct, map and slice.
 	// As to slice, it is standard and unique choice.
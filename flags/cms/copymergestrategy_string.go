@@ -26,26 +26,34 @@ func _() {
 	_ = x[SliceCopy-52]
 	_ = x[SliceCopyAppend-53]
 	_ = x[SliceMerge-54]
+	_ = x[SliceUnion-55]
+	_ = x[SliceDedup-56]
+	_ = x[SlicePrepend-57]
+	_ = x[SliceMergeByIndex-58]
+	_ = x[SliceReplace-59]
 	_ = x[MapCopy-72]
 	_ = x[MapMerge-73]
 	_ = x[Flat-83]
+	_ = x[Wrap-86]
+	_ = x[Strict-87]
+	_ = x[Saturate-88]
 	_ = x[UnexportedToo-93]
 	_ = x[ByOrdinal-94]
 	_ = x[ByName-95]
 	_ = x[Shallow-96]
 	_ = x[MaxStrategy-97]
-	_ = x[ftf100-126]
-	_ = x[ftf110-137]
-	_ = x[ftf120-148]
-	_ = x[ftf130-159]
-	_ = x[ftf140-170]
-	_ = x[ftf150-181]
-	_ = x[ftf160-192]
-	_ = x[ftf170-203]
+	_ = x[ftf100-134]
+	_ = x[ftf110-145]
+	_ = x[ftf120-156]
+	_ = x[ftf130-167]
+	_ = x[ftf140-178]
+	_ = x[ftf150-189]
+	_ = x[ftf160-200]
+	_ = x[ftf170-211]
 	_ = x[InvalidStrategy - -1]
 }
 
-const _CopyMergeStrategy_name = "InvalidStrategystd-mustcleareqkeepneqclearinvalidclearmissednoomitomitemptyomitnilomitzeronoomittgtomitemptytgtomitniltgtomitzerotgtslicecopyslicecopyappendslicemergemapcopymapmergeflatprivatebyordinalbynameshallowMaxStrategyftf100ftf110ftf120ftf130ftf140ftf150ftf160ftf170"
+const _CopyMergeStrategy_name = "InvalidStrategystd-mustcleareqkeepneqclearinvalidclearmissednoomitomitemptyomitnilomitzeronoomittgtomitemptytgtomitniltgtomitzerotgtslicecopyslicecopyappendslicemergesliceunionslicededupsliceprependslicemergeindexslicereplacemapcopymapmergeflatwrapstrictsaturateprivatebyordinalbynameshallowMaxStrategyftf100ftf110ftf120ftf130ftf140ftf150ftf160ftf170"
 
 var _CopyMergeStrategy_map = map[CopyMergeStrategy]string{
 	-1:  _CopyMergeStrategy_name[0:15],
@@ -67,22 +75,30 @@ var _CopyMergeStrategy_map = map[CopyMergeStrategy]string{
 	52:  _CopyMergeStrategy_name[132:141],
 	53:  _CopyMergeStrategy_name[141:156],
 	54:  _CopyMergeStrategy_name[156:166],
-	72:  _CopyMergeStrategy_name[166:173],
-	73:  _CopyMergeStrategy_name[173:181],
-	83:  _CopyMergeStrategy_name[181:185],
-	93:  _CopyMergeStrategy_name[185:192],
-	94:  _CopyMergeStrategy_name[192:201],
-	95:  _CopyMergeStrategy_name[201:207],
-	96:  _CopyMergeStrategy_name[207:214],
-	97:  _CopyMergeStrategy_name[214:225],
-	126: _CopyMergeStrategy_name[225:231],
-	137: _CopyMergeStrategy_name[231:237],
-	148: _CopyMergeStrategy_name[237:243],
-	159: _CopyMergeStrategy_name[243:249],
-	170: _CopyMergeStrategy_name[249:255],
-	181: _CopyMergeStrategy_name[255:261],
-	192: _CopyMergeStrategy_name[261:267],
-	203: _CopyMergeStrategy_name[267:273],
+	55:  _CopyMergeStrategy_name[166:176],
+	56:  _CopyMergeStrategy_name[176:186],
+	57:  _CopyMergeStrategy_name[186:198],
+	58:  _CopyMergeStrategy_name[198:213],
+	59:  _CopyMergeStrategy_name[213:225],
+	72:  _CopyMergeStrategy_name[225:232],
+	73:  _CopyMergeStrategy_name[232:240],
+	83:  _CopyMergeStrategy_name[240:244],
+	86:  _CopyMergeStrategy_name[244:248],
+	87:  _CopyMergeStrategy_name[248:254],
+	88:  _CopyMergeStrategy_name[254:262],
+	93:  _CopyMergeStrategy_name[262:269],
+	94:  _CopyMergeStrategy_name[269:278],
+	95:  _CopyMergeStrategy_name[278:284],
+	96:  _CopyMergeStrategy_name[284:291],
+	97:  _CopyMergeStrategy_name[291:302],
+	134: _CopyMergeStrategy_name[302:308],
+	145: _CopyMergeStrategy_name[308:314],
+	156: _CopyMergeStrategy_name[314:320],
+	167: _CopyMergeStrategy_name[320:326],
+	178: _CopyMergeStrategy_name[326:332],
+	189: _CopyMergeStrategy_name[332:338],
+	200: _CopyMergeStrategy_name[338:344],
+	211: _CopyMergeStrategy_name[344:350],
 }
 
 func (i CopyMergeStrategy) String() string {
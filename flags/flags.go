@@ -310,6 +310,9 @@ func Parse(s reflect.StructTag, tagName string) (flags Flags, targetNameRule Nam
 		if _, ok = flags[k]; ok {
 			continue
 		}
+		if _, ok = mKnownImplicitLeaders[k]; ok {
+			continue // such as wrap, it's the default without being set
+		}
 		for k1 := range mKnownFieldTagFlagsConflict[k] {
 			if _, ok = flags[k1]; ok {
 				break
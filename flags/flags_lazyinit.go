@@ -16,8 +16,10 @@ func lazyInitFieldTagsFlags() {
 		conflictsAdd("noomit", "omitempty", "omitnil", "omitzero")
 		conflictsAdd("noomittgt", "omitemptytgt", "omitniltgt", "omitzerotgt")
 
-		conflictsAdd("slicecopy", "slicecopyappend", "slicemerge")
+		conflictsAdd("slicecopy", "slicecopyappend", "slicemerge",
+			"sliceunion", "slicededup", "sliceprepend", "slicemergeindex", "slicereplace")
 		conflictsAdd("mapcopy", "mapmerge")
+		conflictsAddImplicit("wrap", "strict", "saturate")
 
 		// conflictsAdd("clearinvalid")
 		// conflictsAdd("cleareq")
@@ -29,8 +31,10 @@ func lazyInitFieldTagsFlags() {
 			{cms.ByOrdinal, cms.ByName},
 			{cms.NoOmit, cms.OmitIfEmpty, cms.OmitIfNil, cms.OmitIfZero},
 			{cms.NoOmitTarget, cms.OmitIfTargetEmpty, cms.OmitIfTargetNil, cms.OmitIfTargetZero},
-			{cms.SliceCopy, cms.SliceCopyAppend, cms.SliceMerge},
+			{cms.SliceCopy, cms.SliceCopyAppend, cms.SliceMerge,
+				cms.SliceUnion, cms.SliceDedup, cms.SlicePrepend, cms.SliceMergeByIndex, cms.SliceReplace},
 			{cms.MapCopy, cms.MapMerge},
+			{cms.Wrap, cms.Strict, cms.Saturate},
 			// {cms.ClearIfInvalid},
 			// {cms.ClearIfEq},
 			// {cms.KeepIfNotEq},
@@ -64,6 +68,17 @@ func conflictsAdd(ss ...string) { //nolint:revive
 	}
 }
 
+// conflictsAddImplicit adds a radio group like conflictsAdd, but its
+// leader is an implicit default: Parse doesn't set it if none of the
+// group is given, though IsGroupedFlagOK still reports it.
+func conflictsAddImplicit(ss ...string) {
+	conflictsAdd(ss...)
+	if mKnownImplicitLeaders == nil {
+		mKnownImplicitLeaders = make(map[cms.CopyMergeStrategy]struct{})
+	}
+	mKnownImplicitLeaders[cms.Default.Parse(ss[0])] = struct{}{}
+}
+
 var (
 	onceFieldTagsEquip sync.Once //nolint:gochecknoglobals //i know that
 
@@ -71,6 +86,7 @@ var (
 
 	mKnownFieldTagFlagsConflict        map[cms.CopyMergeStrategy]map[cms.CopyMergeStrategy]struct{} //nolint:lll,gochecknoglobals //i know that
 	mKnownFieldTagFlagsConflictLeaders map[cms.CopyMergeStrategy]struct{}                           //nolint:lll,gochecknoglobals //i know that
+	mKnownImplicitLeaders              map[cms.CopyMergeStrategy]struct{}                           //nolint:lll,gochecknoglobals //i know that
 	mKnownStrategyGroup                []cms.CopyMergeStrategies                                    //nolint:lll,unused,gochecknoglobals //i know that
 // the toggleable radio groups
 )
@@ -15,6 +15,7 @@ func subtest1(t *testing.T) {
 		cms.SliceCopyAppend,
 		cms.OmitIfEmpty, cms.OmitIfTargetEmpty,
 		cms.MapMerge,
+		cms.Strict,
 		cms.Ignore)
 
 	t.Logf("flags: %v", flags)
@@ -143,6 +144,28 @@ func TestFlags2(t *testing.T) { //nolint:revive
 			t.Fatalf("expect isGroupedFlagOK(NoOmitTarget) test ok")
 		}
 	})
+
+	t.Run("numeric conversion flags", func(t *testing.T) {
+		flags := newFlags().WithFlags(cms.Strict, cms.Saturate)
+		if !flags.IsFlagOK(cms.Saturate) || flags.IsFlagOK(cms.Strict) {
+			t.Fatalf("expect saturate toggled off strict, but got %v", flags)
+		}
+
+		flags, _ = Parse(`copy:",saturate,strict"`, CopyTagName)
+		if !flags.IsFlagOK(cms.Strict) || flags.IsAnyFlagsOK(cms.Saturate, cms.Wrap) {
+			t.Fatalf("expect strict only, but got %v", flags)
+		}
+
+		flags, _ = Parse(`copy:""`, CopyTagName)
+		if flags.IsAnyFlagsOK(cms.Wrap, cms.Strict, cms.Saturate) || !flags.IsGroupedFlagOK(cms.Wrap) {
+			t.Fatalf("expect wrap by default without being set, but got %v", flags)
+		}
+
+		flags, _ = Parse(`copy:",wrap"`, CopyTagName)
+		if !flags.IsFlagOK(cms.Wrap) {
+			t.Fatalf("expect wrap, but got %v", flags)
+		}
+	})
 }
 
 func TestFlagsNew(t *testing.T) {
@@ -33,6 +33,12 @@ func copyPointer(c *cpController, params *Params, from, to reflect.Value) (err e
 		}
 	}
 
+	if dst, ok := params.lookupAlias(from, to.Type()); ok && to.CanSet() {
+		dbglog.Log("    pointer - source was copied, reuse the target: %v", ref.Typfmtv(&dst))
+		to.Set(dst) // a shared reference, or a cycle
+		return
+	}
+
 	src := ref.Rindirect(from)
 	tgt := ref.Rindirect(to)
 
@@ -42,6 +48,9 @@ func copyPointer(c *cpController, params *Params, from, to reflect.Value) (err e
 	//nolint:lll //keep it
 	if tgt.CanSet() {
 		if src.IsValid() {
+			if to.Kind() == reflect.Ptr {
+				defer params.registerAlias(from, to)()
+			}
 			err = c.copyTo(paramsChild, src, to)
 		} else if paramsChild.isGroupedFlagOKDeeply(cms.ClearIfInvalid) {
 			// pointer - src is nil - set tgt to nil too
@@ -53,12 +62,13 @@ func copyPointer(c *cpController, params *Params, from, to reflect.Value) (err e
 		}
 	} else {
 		dbglog.Log("    pointer - tgt is invalid/cannot-be-set/ignored: src: (%v) -> tgt: (%v)", ref.Typfmtv(&src), ref.Typfmtv(&to))
-		err = newObj(c, paramsChild, fromType, src, to, tgt)
+		err = newObj(c, paramsChild, from, src, to, tgt)
 	}
 	return
 }
 
-func newObj(c *cpController, params *Params, fromType reflect.Type, src, to, tgt reflect.Value) (err error) {
+func newObj(c *cpController, params *Params, from, src, to, tgt reflect.Value) (err error) {
+	fromType := from.Type()
 	newtyp := to.Type()
 	if to.Type() == fromType {
 		newtyp = newtyp.Elem() // is pointer and its same
@@ -66,8 +76,17 @@ func newObj(c *cpController, params *Params, fromType reflect.Type, src, to, tgt
 	// create new object and pointer
 	toobjcopyptrv := reflect.New(newtyp)
 	dbglog.Log("    toobjcopyptrv: %v", ref.Typfmtv(&toobjcopyptrv))
+	if to.Type() == fromType {
+		// register it before copying so that a cycle can be closed
+		defer params.registerAlias(from, toobjcopyptrv)()
+	}
 	if err = c.copyTo(params, src, toobjcopyptrv.Elem()); err == nil {
 		val := toobjcopyptrv
+		if !tgt.IsValid() && val.Type().AssignableTo(to.Type()) {
+			// to is a nil pointer, point it to the new object
+			err = setTargetValue2(params.owner, to, val)
+			return
+		}
 		if to.Type() == fromType {
 			val = val.Elem()
 		}
@@ -271,6 +290,9 @@ func copyStructInternal( //nolint:revive,gocognit //keep it
 
 	err = fn(paramsChild, ec, &i, &amount, padding)
 	ec.Attach(err)
+	if c.accessors {
+		ec.Attach(c.copyAccessors(paramsChild, from, to))
+	}
 	return
 }
 
@@ -366,45 +388,75 @@ func forEachTargetField(params *Params, ec errors.Error, i, amount *int, padding
 	//nolint:lll //keep it
 	dbglog.Log("     c.autoNewStruct = %v, c.copyFunctionResultToTarget = %v, cms.ClearIfMissed is set: %v", aun, cfrtt, fcz)
 
+	var getters reflect.Value // the source struct pointer to call getters, see WithAccessors
+	if c.accessors && params.srcDecoded != nil {
+		getters = accessorsOf(*params.srcDecoded, false)
+	}
+
 	for *i, *amount = 0, len(sst.TableRecords()); params.nextTargetFieldLite(); *i++ {
 		name := params.accessor.StructFieldName() // get target field name
 		if params.shouldBeIgnored(name) {
+			if name != "" {
+				c.tracer.add(params, name, "", "", TraceSkipped, "ignored by name")
+			}
 			continue
 		}
 
 		if extractor := c.sourceExtractor; extractor != nil { //nolint:nestif //keep it
+			c.tracer.enter(params, name, name, "extractor")
 			v := extractor(name)
 			val = reflect.ValueOf(v)
 			params.accessor.Set(val)
+			c.tracer.result(val)
 			continue
 		}
 
-		srcFieldName, _, ignored := getSourceFieldName(name, params)
+		srcFieldName, flagsInTag, ignored := getSourceFieldName(name, params)
 		if ignored {
 			dbglog.Log(`     > source field ignored (flag found in struct tag): %v.`, srcFieldName)
+			c.tracer.add(params, name, srcFieldName, "", TraceSkipped, "ignored by tag")
 			continue
 		}
 
+		c.tracer.enter(params, name, srcFieldName, traceMatchRule(flagsInTag, "byname"))
 		ind := sst.RecordByName(srcFieldName)
+		if ind == nil && getters.IsValid() {
+			v, gn, e := c.getTargetFieldByGetter(params, getters, srcFieldName)
+			if e != nil {
+				ec.Attach(e)
+				c.tracer.fail(e)
+				continue
+			}
+			if gn != "" {
+				ind = &v
+				c.tracer.match(gn+"()", "getter")
+			}
+		}
 		switch {
 		case ind != nil:
 			val = *ind
 		case cfrtt:
 			if _, ind = sst.MethodCallByName(srcFieldName); ind != nil {
 				val = *ind
+				c.tracer.match(srcFieldName+"()", "method")
 			} else if _, ind = sst.MethodCallByName(name); ind != nil {
 				val = *ind
+				c.tracer.match(name+"()", "method")
 			} else {
+				c.tracer.skip("source missed")
 				continue // skip the field
 			}
 		case fcz || (aun && !params.accessor.ValueValid()):
 			tt := params.accessor.FieldType()
 			val = reflect.Zero(*tt)
 			dbglog.Log("     target is invalid: %v, autoNewStruct: %v", params.accessor.ValueValid(), aun)
+			c.tracer.clear("source missed")
 		default:
+			c.tracer.skip("source missed")
 			continue
 		}
 		params.accessor.Set(val)
+		c.tracer.result(val)
 	}
 	return
 }
@@ -416,6 +468,7 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 	for *i, *amount = 0, len(sst.TableRecords()); *i < *amount; *i++ {
 		if params.sourceFieldShouldBeIgnored() {
 			dbglog.Log("%d. %s : IGNORED", *i, sst.CurrRecord().FieldName())
+			c.tracer.add(params, sst.CurrRecord().FieldName(), sst.CurrRecord().FieldName(), "", TraceSkipped, "ignored")
 			if c.advanceTargetFieldPointerEvenIfSourceIgnored {
 				_ = params.nextTargetFieldLite()
 			} else {
@@ -431,6 +484,9 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 
 		var sourceField *tableRecT
 		if sourceField, goon = params.nextTargetField(); !goon {
+			if sourceField != nil {
+				c.tracer.add(params, tracePath(sourceField, params.accessor.StructFieldName()), sourceField.FieldName(), "", TraceSkipped, "ignored by tag") //nolint:lll
+			}
 			continue
 		}
 
@@ -449,6 +505,7 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 		fn, srcval, dstval := sourceField.FieldName(), sourceField.FieldValue(), params.accessor.FieldValue()
 
 		dstfieldname := params.accessor.StructFieldName()
+		c.tracer.enter(params, tracePath(sourceField, dstfieldname), fn, traceMatchRule(flagsInTag, "byordinal"))
 		// log.VDebugf will be tuned and stripped off in normal build.
 		dbglog.Colored(color.FgLightMagenta, "%d. fld %q (%v) -> %s (%v) | (%v) -> (%v)", *i,
 			fn, ref.Typfmtv(srcval), dstfieldname, ref.Typfmt(*params.accessor.FieldType()),
@@ -478,6 +535,7 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 
 			if shallow {
 				dbglog.Log("   > src field is shallow: %v (val: %v)", ref.Typfmtv(srcval), ref.Valfmt(srcval))
+				c.tracer.note("shallow")
 				err = copyDefaultHandler(c, params, *srcval, *dstval)
 				continue
 			}
@@ -485,16 +543,20 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 			if srcval.IsValid() {
 				if err = invokeStructFieldTransformer(c, params, srcval, dstval, typ1, padding); err != nil {
 					ec.Attach(err)
+					c.tracer.fail(err)
 					dbglog.Err("    %d. fld %q error: %v", *i, fn, err)
 				} else {
 					dbglog.Log("    %d. fld %q copied. from-to: %v -> %v", *i, fn, ref.Valfmt(srcval), ref.Valfmt(dstval))
 				}
+			} else {
+				c.tracer.skip("invalid source")
 			}
 			continue
 		}
 
 		if shallow {
 			dbglog.Log("    > src field is shallow: %v (val: %v)", ref.Typfmtv(srcval), ref.Valfmt(srcval))
+			c.tracer.note("shallow")
 			err = copyDefaultHandler(c, params, *srcval, *dstval)
 			continue
 		}
@@ -504,6 +566,7 @@ func forEachSourceField(params *Params, ec errors.Error, i, amount *int, padding
 		}
 
 		dbglog.Wrn("   %d. fld %q: ignore nil/zero/invalid source or nil target", *i, fn)
+		c.tracer.skip("nil/zero/invalid source or nil target")
 	}
 	return
 }
@@ -517,6 +580,7 @@ func forEachSourceFieldCheckTargetSetter(params *Params, sst sourceStructFieldsT
 	currec := sst.CurrRecord()
 	srcval := currec.FieldValue()
 	if err = c.targetSetter(srcval, currec.names...); err == nil {
+		c.tracer.add(params, currec.FieldName(), currec.FieldName(), "setter", TraceCopied, "")
 		if c.advanceTargetFieldPointerEvenIfSourceIgnored {
 			_ = params.nextTargetFieldLite()
 		} else {
@@ -602,6 +666,12 @@ func invokeStructFieldTransformer( //nolint:revive
 	if processed = checkOmitEmptyOpt(params, ff, df, dft); processed {
 		return
 	}
+	if fv && dv {
+		// iterator source, or user collection target
+		if processed, err = c.copyCollection(params, *ff, *df); processed {
+			return
+		}
+	}
 	if processed, err = tryConverters(c, params, ff, df, dftyp, false); processed {
 		return
 	}
@@ -680,6 +750,9 @@ func checkOmitEmptyOpt(params *Params, ff, df *reflect.Value, dft reflect.Type)
 	if ref.IsZerov(ff) && params.isGroupedFlagOKDeeply(cms.OmitIfZero, cms.OmitIfEmpty) {
 		processed = true
 	}
+	if processed {
+		params.tracer().skip("omitted nil/zero source")
+	}
 	_, _ = df, dft
 	return
 }
@@ -688,8 +761,12 @@ func checkClearIfEqualOpt(params *Params, ff, df *reflect.Value, dft reflect.Typ
 	if params.isFlagExists(cms.ClearIfEq) {
 		if tool.EqualClassical(*ff, *df) {
 			df.Set(reflect.Zero(dft))
+			params.tracer().clear("clearifeq")
 		} else if params.isFlagExists(cms.ClearIfInvalid) && !df.IsValid() {
 			df.Set(reflect.Zero(dft))
+			params.tracer().clear("clearifinvalid")
+		} else {
+			params.tracer().skip("keepifnoteq")
 		}
 		processed = true
 		if params.isFlagExists(cms.KeepIfNotEq) {
@@ -722,6 +799,7 @@ func tryConverters(c *cpController, params *Params,
 func findAndApplyCopiers(c *cpController, params *Params, ff, df *reflect.Value, fft, dft reflect.Type, userDefinedOnly bool) (processed bool, err error) { //nolint:revive,lll
 	if cvt, ctx := c.valueCopiers.findCopiers(params, fft, dft, userDefinedOnly); ctx != nil { //nolint:nestif //keep it
 		dbglog.Colored(color.FgDarkColor, "-> using Copier %v", reflect.ValueOf(cvt).Type())
+		c.tracer.copier(cvt)
 
 		if df.IsValid() {
 			if err = cvt.CopyTo(ctx, *safeFF(ff, fft), *df); err == nil { // use user-defined copy-n-merger to merge or copy source to destination
@@ -747,10 +825,15 @@ func findAndApplyCopiers(c *cpController, params *Params, ff, df *reflect.Value,
 func findAndApplyConverters(c *cpController, params *Params, ff, df *reflect.Value, fft, dft reflect.Type, userDefinedOnly bool) (processed bool, err error) { //nolint:revive,lll
 	if cvt, ctx := c.valueConverters.findConverters(params, fft, dft, userDefinedOnly); ctx != nil {
 		dbglog.Colored(color.FgDarkColor, "-> using Converter %v", reflect.ValueOf(cvt).Type())
+		c.tracer.converter(cvt)
 
 		var result reflect.Value
 		result, err = cvt.Transform(ctx, *safeFF(ff, fft), dft) // use user-defined value converter to transform from source to destination
-		if err == nil && !df.IsValid() && !params.accessor.IsStruct() {
+		if err != nil {
+			processed = true
+			return
+		}
+		if !df.IsValid() && !params.accessor.IsStruct() {
 			params.accessor.Set(result)
 			processed = true
 			return
@@ -793,7 +876,9 @@ func copySlice(c *cpController, params *Params, from, to reflect.Value) (err err
 	if tk != reflect.Slice {
 		dbglog.Log("[copySlice] from slice -> %v", ref.Typfmt(typ1))
 		var processed bool
-		if processed, err = tryConverters(c, params, &from, &tgt, &typ1, false); !processed {
+		if processed, err = tryConverters(c, params, &from, &tgt, &typ1, false); !processed && tk == reflect.Array {
+			err = c.copyToArray(params, from, tgt)
+		} else if !processed {
 			// logz.Panicf("[copySlice] unsupported transforming: from slice -> %v,", typfmtv(&tgt))
 			//nolint:lll //keep it
 			err = ErrCannotCopy.WithErrors(err).FormatWith(ref.Valfmt(&from), ref.Typfmtv(&from), ref.Valfmt(&tgt), ref.Typfmtv(&tgt))
@@ -805,6 +890,12 @@ func copySlice(c *cpController, params *Params, from, to reflect.Value) (err err
 		return
 	}
 
+	if dst, ok := params.lookupAlias(from, typ1); ok && tgt.CanSet() {
+		tgt.Set(dst) // a shared slice, or a self-referencing one
+		params.resultForNewSlice = &dst
+		return
+	}
+
 	params.resultForNewSlice, err = copySliceInternal(c, params, from, to, tgt, tgtptr)
 	return
 }
@@ -818,7 +909,10 @@ func copySliceInternal(c *cpController, params *Params, from, to, tgt, tgtptr re
 	ec := errors.New("slice copy/merge errors")
 	defer ec.Defer(&err)
 
-	for _, flag := range []cms.CopyMergeStrategy{cms.SliceMerge, cms.SliceCopyAppend, cms.SliceCopy} {
+	for _, flag := range []cms.CopyMergeStrategy{
+		cms.SliceUnion, cms.SliceDedup, cms.SlicePrepend, cms.SliceMergeByIndex, cms.SliceReplace,
+		cms.SliceMerge, cms.SliceCopyAppend, cms.SliceCopy,
+	} {
 		if params.isGroupedFlagOKDeeply(flag) { //nolint:revive,nestif,gocritic,lll // nestingReduce: invert if cond, replace body with `continue`, move old body after the statement
 			dbglog.Log("Using slice merge mode: %v", flag)
 			dbglog.Log("  from.type: %v, value: %v", ref.Typfmtv(&from), ref.Valfmt(&from))
@@ -828,6 +922,7 @@ func copySliceInternal(c *cpController, params *Params, from, to, tgt, tgtptr re
 
 			if fn, ok := getSliceOperations()[flag]; ok {
 				if result, err = fn(c, params, from, tgt); err == nil {
+					defer params.registerAlias(from, *result)() // for the next reference to from
 					dbglog.Log("     result: got %v (%v)", ref.Valfmt(result), ref.Typfmtv(result))
 					dbglog.Log("        tgt: contains %v (%v) | tgtptr: %v, .canset: %v", ref.Valfmt(&tgt), ref.Typfmtv(&tgt), ref.Typfmtv(&tgtptr), tgtptr.CanSet()) //nolint:revive,lll
 
@@ -890,35 +985,41 @@ func getSliceOperations() (mapOfSliceOperations mSliceOperations) {
 		cms.SliceCopy:       _sliceCopyOperation,
 		cms.SliceCopyAppend: _sliceCopyAppendOperation,
 		cms.SliceMerge:      _sliceMergeOperation,
+
+		cms.SliceUnion:        _sliceUnionOperation,
+		cms.SliceDedup:        _sliceDedupOperation,
+		cms.SlicePrepend:      _slicePrependOperation,
+		cms.SliceMergeByIndex: _sliceMergeByIndexOperation,
+		cms.SliceReplace:      _sliceReplaceOperation,
 	}
 	return
 }
 
 // _sliceCopyOperation: for SliceCopy, target elements will be given up, and source copied to.
+//
+// The new slice is registered as the copy of src before copying the
+// elements, so a self-referencing slice is closed in target, and a
+// shared one is mapped 1:1 with WithPreserveAliasing.
 func _sliceCopyOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) {
-	slice := reflect.MakeSlice(tgt.Type(), 0, src.Len())
+	sl := src.Len()
+	slice := reflect.MakeSlice(tgt.Type(), sl, sl)
 	dbglog.Log("tgt slice: %v, el: %v", tgt.Type(), tgt.Type().Elem())
+	defer params.registerAlias(src, slice)()
 
 	ecTotal := errors.New("slice merge errors (%v -> %v)", src.Type(), tgt.Type())
 	defer ecTotal.Defer(&err)
 
-	// if c.wipeSlice1st {
-	// 	// TODO c.wipeSlice1st
-	// }
-
-	for _, ss := range []struct {
-		length int
-		source reflect.Value
-	}{
-		// {tl, tgt},
-		{src.Len(), src},
-	} {
-		var one *reflect.Value
-		one, err = _sliceCopyOne(c, params, ecTotal, slice, ss.length, ss.source, tgt)
-		if one != nil && err == nil {
-			slice = *one
+	n := 0
+	for i := 0; i < sl; i++ {
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue // ignore invalid element
 		}
+		slice.Index(n).Set(el)
+		n++
 	}
+	slice = slice.Slice(0, n)
 	result = &slice
 	return
 }
@@ -969,12 +1070,21 @@ func _sliceCopyOne(c *cpController, params *Params, ecTotal errors.Error, slice
 					continue // ignore invalid element
 				}
 			} else if ref.CanConvert(&el, tgtelemtype) {
-				enew = el.Convert(tgtelemtype)
+				if enew, err = convertNumber(el, tgtelemtype, params.numConvMode()); err != nil {
+					ecTotal.Attach(err)
+					continue
+				}
 				// elv = enew.Interface()
 			}
 		}
 
 		if el.Type() == tgtelemtype { //nolint:nestif //keep it
+			if sssource.Pointer() != tgt.Pointer() {
+				if el, err = cloneSliceElem(c, params, el); err != nil {
+					ecTotal.Attach(err)
+					continue
+				}
+			}
 			slice = reflect.Append(slice, el) //nolint:revive
 		} else {
 			if ref.CanConvert(&el, tgtelemtype) {
@@ -997,6 +1107,33 @@ func _sliceCopyOne(c *cpController, params *Params, ecTotal errors.Error, slice
 	return
 }
 
+// cloneSliceElem returns a copy of the object pointed by a pointer
+// element (or by an interface element holding a pointer, map or
+// slice) in cloning or WithPreserveAliasing mode, so that the cycles
+// through the elements are closed in target, and the shared elements
+// are mapped 1:1. The other elements, and the references in the plain
+// copying, are returned as is (shared with the source).
+func cloneSliceElem(c *cpController, params *Params, el reflect.Value) (enew reflect.Value, err error) {
+	enew = el
+	if !c.makeNewClone && !c.preserveAliasing || params.isAnyFlagsOK(cms.Flat, cms.Shallow) {
+		return
+	}
+	switch k := el.Kind(); {
+	case k == reflect.Ptr && !el.IsNil():
+		ptr := reflect.New(el.Type())
+		if err = c.copyTo(params, el, ptr.Elem()); err == nil {
+			enew = ptr.Elem()
+		}
+	case k == reflect.Interface && !el.IsNil() && ref.KindIs(el.Elem().Kind(), reflect.Ptr, reflect.Map, reflect.Slice):
+		nv := reflect.New(el.Elem().Type()).Elem()
+		if err = c.copyTo(params, el.Elem(), nv); err == nil {
+			enew = reflect.New(el.Type()).Elem()
+			enew.Set(nv)
+		}
+	}
+	return
+}
+
 // _sliceMergeOperation: for SliceMerge. target and source elements will be
 // copied to new target with uniqueness.
 func _sliceMergeOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) { //nolint:revive
@@ -1038,12 +1175,23 @@ func _sliceMergeOperation(c *cpController, params *Params, src, tgt reflect.Valu
 					}
 					cvtok, elv = true, enew.Interface()
 				} else if ref.CanConvert(&el, tgtelemtype) {
-					enew = el.Convert(tgtelemtype)
+					if enew, err = convertNumber(el, tgtelemtype, params.numConvMode()); err != nil {
+						ec.Attach(err)
+						ecTotal.Attach(ec)
+						continue
+					}
 					cvtok, elv = true, enew.Interface()
 				}
 			}
 
 			if found = tool.FindInSlice(ns, elv, i); !found { //nolint:nestif //keep it
+				if !cvtok && elt == tgtelemtype && ss.source.Pointer() != tgt.Pointer() {
+					if enew, err = cloneSliceElem(c, params, el); err != nil {
+						ec.Attach(err)
+						ecTotal.Attach(ec)
+						continue
+					}
+				}
 				if cvtok || elt == tgtelemtype {
 					ns = reflect.Append(ns, enew)
 				} else {
@@ -1066,6 +1214,219 @@ func _sliceMergeOperation(c *cpController, params *Params, src, tgt reflect.Valu
 	return
 }
 
+// _sliceUnionOperation: for SliceUnion. target and source elements will be
+// copied to new target with uniqueness, the source elements are compared
+// after converted.
+func _sliceUnionOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) {
+	ns := reflect.MakeSlice(tgt.Type(), 0, tgt.Len()+src.Len())
+
+	ecTotal := errors.New("slice union errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ecTotal.Defer(&err)
+
+	for i := 0; i < tgt.Len(); i++ {
+		if el := tgt.Index(i); !tool.FindInSlice(ns, el.Interface(), i) {
+			ns = reflect.Append(ns, el)
+		}
+	}
+	for i := 0; i < src.Len(); i++ {
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue
+		}
+		if !tool.FindInSlice(ns, el.Interface(), i) {
+			ns = reflect.Append(ns, el)
+		}
+	}
+	result = &ns
+	return
+}
+
+// _sliceDedupOperation: for SliceDedup. target and source elements will be
+// copied to new target with the unique keys, a source element replaces the
+// one with the same key in place.
+func _sliceDedupOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) {
+	ns := reflect.MakeSlice(tgt.Type(), 0, tgt.Len()+src.Len())
+	keyOf := params.sliceDedupKey()
+
+	ecTotal := errors.New("slice dedup errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ecTotal.Defer(&err)
+
+	var keys []any
+	indexOf := func(key any) int {
+		for j, k := range keys {
+			if reflect.DeepEqual(k, key) {
+				return j
+			}
+		}
+		return -1
+	}
+
+	for i := 0; i < tgt.Len(); i++ {
+		el := tgt.Index(i)
+		key, e := keyOf(el)
+		if e != nil {
+			ecTotal.Attach(e)
+			return
+		}
+		if indexOf(key) < 0 {
+			keys, ns = append(keys, key), reflect.Append(ns, el)
+		}
+	}
+	for i := 0; i < src.Len(); i++ {
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue
+		}
+		key, e := keyOf(el)
+		if e != nil {
+			ecTotal.Attach(e)
+			return
+		}
+		if j := indexOf(key); j >= 0 {
+			dbglog.Log("  src[%v] replaces the element with key %v", i, key)
+			ns.Index(j).Set(el)
+			continue
+		}
+		keys, ns = append(keys, key), reflect.Append(ns, el)
+	}
+	result = &ns
+	return
+}
+
+// _slicePrependOperation: for SlicePrepend. source elements will be
+// inserted before the target ones.
+func _slicePrependOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) {
+	ns := reflect.MakeSlice(tgt.Type(), 0, tgt.Len()+src.Len())
+
+	ecTotal := errors.New("slice prepend errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ecTotal.Defer(&err)
+
+	for i := 0; i < src.Len(); i++ {
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue
+		}
+		ns = reflect.Append(ns, el)
+	}
+	ns = reflect.AppendSlice(ns, tgt)
+	result = &ns
+	return
+}
+
+// _sliceMergeByIndexOperation: for SliceMergeByIndex. each source element
+// will be merged into the target element at the same index, and the extra
+// ones are appended.
+func _sliceMergeByIndexOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) { //nolint:lll
+	sl, tl := src.Len(), tgt.Len()
+	ns := reflect.MakeSlice(tgt.Type(), tl, max(sl, tl))
+	reflect.Copy(ns, tgt)
+
+	ecTotal := errors.New("slice merge-by-index errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ecTotal.Defer(&err)
+
+	for i := 0; i < sl; i++ {
+		if i < tl {
+			el, to := src.Index(i), ns.Index(i)
+			ecTotal.Attach(c.copyTo(newParams(withOwners(c, params, &el, &to, &el, &to)), el, to))
+			continue
+		}
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue
+		}
+		ns = reflect.Append(ns, el)
+	}
+	result = &ns
+	return
+}
+
+// _sliceReplaceOperation: for SliceReplace. source elements will replace
+// the target ones at the same index, but the target length is kept.
+func _sliceReplaceOperation(c *cpController, params *Params, src, tgt reflect.Value) (result *reflect.Value, err error) {
+	ns := reflect.MakeSlice(tgt.Type(), tgt.Len(), tgt.Len())
+	reflect.Copy(ns, tgt)
+
+	ecTotal := errors.New("slice replace errors (%v -> %v)", src.Type(), tgt.Type())
+	defer ecTotal.Defer(&err)
+
+	for i := 0; i < src.Len() && i < ns.Len(); i++ {
+		el, e := convertSliceElem(c, params, src, tgt, i)
+		if e != nil {
+			ecTotal.Attach(e)
+			continue
+		}
+		ns.Index(i).Set(el)
+	}
+	result = &ns
+	return
+}
+
+// convertSliceElem returns a copy of src[i] in the element type of tgt.
+// A registered converter for the element types is tried first, as
+// _sliceCopyOne does, then the number conversion, then copyTo.
+func convertSliceElem(c *cpController, params *Params, src, tgt reflect.Value, i int) (enew reflect.Value, err error) {
+	el, tgtelemtype := src.Index(i), tgt.Type().Elem()
+	if el.Type() == tgtelemtype {
+		if src.Pointer() == tgt.Pointer() {
+			return el, nil
+		}
+		return cloneSliceElem(c, params, el)
+	}
+	if cc, ctx := c.valueConverters.findConverters(params, el.Type(), tgtelemtype, false); cc != nil {
+		enew, err = cc.Transform(ctx, el, tgtelemtype)
+	} else if ref.CanConvert(&el, tgtelemtype) {
+		enew, err = convertNumber(el, tgtelemtype, params.numConvMode())
+	} else {
+		ptr := reflect.New(tgtelemtype)
+		err, enew = c.copyTo(params, el, ptr), ptr.Elem()
+	}
+	if err != nil {
+		ec := errors.New("cannot convert %v to %v", el.Type(), tgtelemtype)
+		ec.Attach(err)
+		return enew, ec
+	}
+	return
+}
+
+// sliceDedupKey returns the key func for SliceDedup, by the `dedupkey=`
+// tag of the nearest target field, or by WithSliceDedupKey. The key is
+// the element itself if neither is specified. The key func reports
+// ErrNoDedupKey if an element has no such field or map key, a nil
+// element is keyed by itself.
+func (params *Params) sliceDedupKey() func(el reflect.Value) (key any, err error) {
+	var name string
+	if tags := params.nearestFieldTags(); tags != nil {
+		name = tags.dedupKey
+	}
+	if name == "" && params != nil && params.controller != nil {
+		if fn := params.controller.sliceDedupKey; fn != nil {
+			return func(el reflect.Value) (any, error) { return fn(el.Interface()), nil }
+		}
+	}
+	return func(el reflect.Value) (key any, err error) {
+		if name == "" || ref.IsNil(el) {
+			return el.Interface(), nil
+		}
+		switch v := ref.Rdecodesimple(el); v.Kind() { //nolint:exhaustive //others have no keys
+		case reflect.Struct:
+			if fv := v.FieldByName(name); fv.IsValid() && fv.CanInterface() {
+				return fv.Interface(), nil
+			}
+		case reflect.Map:
+			if v.Type().Key().Kind() == reflect.String {
+				if mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key())); mv.IsValid() {
+					return mv.Interface(), nil
+				}
+			}
+		}
+		return nil, ErrNoDedupKey.FormatWith(name, ref.Valfmt(&el), ref.Typfmtv(&el))
+	}
+}
+
 func copyArray(c *cpController, params *Params, from, to reflect.Value) (err error) { //nolint:revive
 	if ref.IsZero(from) && params.isGroupedFlagOKDeeply(cms.OmitIfZero, cms.OmitIfEmpty) {
 		return
@@ -1096,6 +1457,10 @@ func copyArray(c *cpController, params *Params, from, to reflect.Value) (err err
 		if processed, err = tryConverters(c, params, &from, &tgt, &tgttyp, false); processed {
 			return
 		}
+		if tk == reflect.Slice {
+			err = c.copyArrayToSlice(params, src, tgt, tgtptr)
+			return
+		}
 		// logz.Panicf("[copySlice] unsupported transforming: from slice -> %v,", typfmtv(&tgt))
 		err = ErrCannotCopy.FormatWith(ref.Valfmt(&src), ref.Typfmtv(&src), ref.Valfmt(&tgt), ref.Typfmtv(&tgt))
 		return
@@ -1105,35 +1470,7 @@ func copyArray(c *cpController, params *Params, from, to reflect.Value) (err err
 		return
 	}
 
-	sl, tl := src.Len(), tgt.Len()
-	eltyp := tgt.Type().Elem()
-	// set := src.Index(0).Type()
-	// if set != tgt.Index(0).Type() {
-	//	return errors.New("cannot copy %v to %v", from.Interface(), to.Interface())
-	// }
-
-	cnt := tool.MinInt(sl, tl)
-	for i := 0; i < cnt; i++ {
-		se := src.Index(i)
-		setyp := se.Type()
-		dbglog.Log("src.el.typ: %v, tgt.el.typ: %v", ref.Typfmt(setyp), eltyp)
-		if se.IsValid() {
-			if setyp.AssignableTo(eltyp) {
-				tgt.Index(i).Set(se)
-			} else if setyp.ConvertibleTo(eltyp) {
-				tgt.Index(i).Set(src.Convert(eltyp))
-			}
-		}
-		// tgt.Index(i).Set(src.Index(i))
-	}
-
-	for i := cnt; i < tl; i++ {
-		v := tgt.Index(i)
-		if !v.IsValid() {
-			tgt.Index(i).Set(reflect.Zero(eltyp))
-			dbglog.Log("set [%v] to zero value", i)
-		}
-	}
+	err = c.copyToArray(params, src, tgt)
 
 	// to.Set(pt.Elem())
 
@@ -1171,6 +1508,11 @@ func copyMap(c *cpController, params *Params, from, to reflect.Value) (err error
 		return
 	}
 
+	if dst, ok := params.lookupAlias(from, typ1); ok && tgt.CanSet() {
+		tgt.Set(dst) // a shared map, or a self-referencing one
+		return
+	}
+
 	ec := errors.New("map copy/merge errors")
 	defer dbglog.DeferVisit(ec, &err)
 
@@ -1200,39 +1542,72 @@ type (
 func getMapOperations() (mMapOperations mapMapOperations) { //nolint:revive
 	mMapOperations = mapMapOperations{ //nolint:exhaustive //i have right
 		cms.MapCopy: func(c *cpController, params *Params, src, tgt, tgtptr reflect.Value) (err error) { //nolint:revive,lll
-			tgt.Set(reflect.MakeMap(src.Type()))
+			keys, cloned, err := c.mapKeysOf(params, src, tgt)
+			if err != nil {
+				return
+			}
+
+			m := reflect.MakeMap(tgt.Type())
+			tgt.Set(m)
+			defer params.registerAlias(src, m)()
 
 			ec := errors.New("map copy errors")
 			defer ec.Defer(&err)
 
-			for _, key := range src.MapKeys() {
-				originalValue := src.MapIndex(key)
+			for i, key := range keys {
+				originalValue, e := c.stringKeyed(params, src.MapIndex(key), tgt.Type())
+				ec.Attach(e)
 				_, copyValueElem := newFromType(tgt.Type().Elem())
 				ec.Attach(c.copyTo(params, originalValue, copyValueElem))
 
-				copyKey := reflect.New(tgt.Type().Key())
-				ec.Attach(c.copyTo(params, key, copyKey.Elem()))
+				var copyKey reflect.Value
+				if cloned != nil && cloned[i].IsValid() {
+					copyKey = cloned[i] // cloned and transformed by mapKeysOf
+				} else {
+					copyKey = reflect.New(tgt.Type().Key()).Elem()
+					ec.Attach(c.copyTo(params, key, copyKey))
+					params.transformMapKey(copyKey)
+				}
 
-				if c.targetSetter != nil && copyKey.Elem().Kind() == reflect.String {
+				if c.targetSetter != nil && copyKey.Kind() == reflect.String {
 					srcval := copyValueElem
-					err = c.targetSetter(&srcval, copyKey.Elem().String())
+					err = c.targetSetter(&srcval, copyKey.String())
 					if err == nil || err != ErrShouldFallback { //nolint:revive
 						return
 					}
 					err = nil
 				}
-				trySetMapIndex(c, params, tgt, copyKey.Elem(), copyValueElem)
+				trySetMapIndex(c, params, tgt, copyKey, copyValueElem)
 			}
 			return
 		},
 		cms.MapMerge: func(c *cpController, params *Params, src, tgt, tgtptr reflect.Value) (err error) {
+			keys, cloned, err := c.mapKeysOf(params, src, tgt)
+			if err != nil {
+				return
+			}
+			if _, _, err = params.mapMergePolicy(); err != nil {
+				return
+			}
+
 			ec := errors.New("map merge errors")
 			defer ec.Defer(&err)
 
-			for _, key := range src.MapKeys() {
+			if tgt.IsNil() && tgt.CanSet() {
+				tgt.Set(reflect.MakeMap(tgt.Type())) // merge into a new map
+			}
+			if !tgt.IsNil() {
+				defer params.registerAlias(src, tgt)()
+			}
+
+			for i, key := range keys {
 				// dbglog.Log("------------ [MapMerge] mergeOneKeyInMap: key = %q (%v) ------------------",
 				// 	tool.Valfmt(&key), tool.Typfmtv(&key))
-				ec.Attach(mergeOneKeyInMap(c, params, src, tgt, tgtptr, key))
+				var ck reflect.Value
+				if cloned != nil {
+					ck = cloned[i]
+				}
+				ec.Attach(mergeOneKeyInMap(c, params, src, tgt, tgtptr, key, ck))
 			}
 			return
 		},
@@ -1255,18 +1630,28 @@ func mapMergePreSetter(c *cpController, ck, cv reflect.Value) (processed bool, e
 }
 
 // mergeOneKeyInMap copy one (key, value) pair in src map to tgt map.
-func mergeOneKeyInMap(c *cpController, params *Params, src, tgt, tgtptr, key reflect.Value) (err error) { //nolint:revive,unparam
+// The key is cloned into ck for tgt map unless ck is valid.
+func mergeOneKeyInMap(c *cpController, params *Params, src, tgt, tgtptr, key, ck reflect.Value) (err error) { //nolint:revive,unparam,lll
 	var processed bool
 
 	dbglog.Colored(color.FgLightMagenta, "      <MAP> copying key '%v': (%v) -> (?)", ref.Valfmt(&key), ref.Valfmtv(src.MapIndex(key)))
 
-	var ck reflect.Value
-	if ck, err = cloneMapKey(c, params, tgt, key); err != nil {
-		return
+	if !ck.IsValid() {
+		if ck, err = cloneMapKey(c, params, tgt, key); err != nil {
+			return
+		}
 	}
 
 	_, _ = tgtptr, tgt
-	originalValue := src.MapIndex(key)
+	originalValue, err := c.stringKeyed(params, src.MapIndex(key), tgt.Type())
+	if err != nil {
+		return
+	}
+	if old := tgt.MapIndex(ck); old.IsValid() && !ref.IsNil(old) {
+		if processed, err = c.resolveMapMerge(params, tgt, ck, old, originalValue); processed {
+			return
+		}
+	}
 
 	tgtval, newelemcreated, err2 := ensureMapPtrValue(c, params, tgt, ck, originalValue)
 	if err = err2; err2 != nil {
@@ -1294,7 +1679,7 @@ func mergeOneKeyInMap(c *cpController, params *Params, src, tgt, tgtptr, key ref
 		var tt reflect.Type
 		tgtvalind, _ := ref.Rdecode(tgtval)
 		if !tgtval.IsValid() || ref.IsZero(tgtval) {
-			srcval := src.MapIndex(ck)
+			srcval := originalValue
 			if !srcval.IsValid() || ref.IsZero(srcval) {
 				tgtvalind = srcval
 				tt = tgtvalind.Type()
@@ -1497,6 +1882,7 @@ func cloneMapKey(c *cpController, params *Params, tgt, key reflect.Value) (ck re
 		dbglog.Err("     cloneMapKey(%v) error on copyTo: %+v", ref.Valfmt(&key), err) // early break-point here
 		return
 	}
+	params.transformMapKey(ck)
 
 	dbglog.Log("         <KEY> cloned: '%v'", ref.Valfmtptr(&ck))
 	return
@@ -1561,17 +1947,32 @@ func copyFunc(c *cpController, params *Params, from, to reflect.Value) (err erro
 
 func copyChan(c *cpController, params *Params, from, to reflect.Value) (err error) { //nolint:revive
 	tgt := ref.Rindirect(to)
-	if tgt.CanSet() {
-		dbglog.Log("    copy chan: %v (%v) -> %v (%v)", from.Kind(), from.Type(), tgt.Kind(), tgt.Type())
-		tgt.Set(from)
-		// Log("        after: %v -> %v", from.Interface(), tgt.Interface())
-	} else {
-		v := reflect.MakeChan(from.Type(), from.Cap())
-		to.Set(v)
-		// // to.SetPointer(from.Pointer())
-		// dbglog.Log("    copy chan not support: %v (%v) -> %v (%v)", from.Kind(), from.Type(), to.Kind(), to.Type())
-		// err = ErrCannotCopy.FormatWith(tool.Valfmt(&from), tool.Typfmtv(&from), tool.Valfmt(&to), tool.Typfmtv(&to))
+	if !tgt.CanSet() {
+		tgt = to
 	}
+	typ := tgt.Type()
+	strategy, err := params.chanStrategy()
+	if err != nil {
+		return
+	}
+	dbglog.Log("    copy chan (%v): %v (%v) -> %v (%v)", strategy, from.Kind(), from.Type(), tgt.Kind(), typ)
+
+	var ch reflect.Value
+	switch {
+	case from.IsNil():
+		ch = reflect.Zero(typ)
+	case strategy == ChanShare && from.Type().AssignableTo(typ):
+		ch = from
+	case strategy == ChanShare && from.Type().ConvertibleTo(typ):
+		ch = from.Convert(typ) // such as chan T -> <-chan T
+	case strategy == ChanFresh:
+		ch = makeChan(typ, from.Cap())
+	default: // snapshot, or share the channel of a different element type
+		if ch, err = c.snapshotChan(params, from, typ); !ch.IsValid() {
+			return
+		} // or else it's set even if the source cannot be refilled
+	}
+	tgt.Set(ch)
 	return
 }
 
@@ -1582,6 +1983,7 @@ func copyDefaultHandler(c *cpController, params *Params, from, to reflect.Value)
 
 	if c != nil {
 		if cvt, ctx := c.valueCopiers.findCopiers(params, sourceType, targetType, false); cvt != nil {
+			c.tracer.copier(cvt)
 			err = cvt.CopyTo(ctx, from, to)
 			return
 		}
@@ -1629,13 +2031,17 @@ func tryCopyPrimitiveToPrimitive(params *Params, //nolint:revive
 ) (stop bool, err error) {
 	stop = true
 	if ref.CanConvert(&fromind, toIndType) {
-		val := fromind.Convert(toIndType)
-		err = setTargetValue1(params, to, toind, val)
+		var val reflect.Value
+		if val, err = convertNumber(fromind, toIndType, params.numConvMode()); err == nil {
+			err = setTargetValue1(params, to, toind, val)
+		}
 		return
 	}
 	if ref.CanConvert(&from, to.Type()) && to.CanSet() {
-		val := from.Convert(to.Type())
-		err = setTargetValue1(params, to, toind, val)
+		var val reflect.Value
+		if val, err = convertNumber(from, to.Type(), params.numConvMode()); err == nil {
+			err = setTargetValue1(params, to, toind, val)
+		}
 		return
 	}
 	if sourceType.AssignableTo(targetType) {
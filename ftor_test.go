@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"testing"
 
+	"gopkg.in/hedzr/errors.v3"
+
 	"github.com/hedzr/evendeep/flags/cms"
 	"github.com/hedzr/evendeep/ref"
 	"github.com/hedzr/evendeep/typ"
@@ -186,6 +188,76 @@ func TestCopySlice_mergeMode(t *testing.T) {
 	}
 }
 
+func TestCopySlice_moreModes(t *testing.T) {
+	c := newCopier()
+
+	type item struct {
+		ID   int
+		Name string
+	}
+
+	for _, tc := range []struct {
+		flag     cms.CopyMergeStrategy
+		src, tgt typ.Any
+		expect   typ.Any
+	}{
+		{cms.SliceUnion, []int{15, 2, 15}, []int{2, 9, 2}, []int{2, 9, 15}},
+		{cms.SliceUnion, []string{"3", "1"}, []int{1, 2}, []int{1, 2, 3}},
+		{cms.SliceDedup, []int{15, 2}, []int{2, 9, 2}, []int{2, 9, 15}},
+		{cms.SlicePrepend, []int{15, 2}, []int{2, 9}, []int{15, 2, 2, 9}},
+		{cms.SlicePrepend, []string{"7"}, []int{}, []int{7}},
+		{cms.SliceMergeByIndex, []int{15, 2, 3}, []int{2, 9}, []int{15, 2, 3}},
+		{cms.SliceReplace, []int{15, 2, 3}, []int{2, 9}, []int{15, 2}},
+		{cms.SliceReplace, []int{15}, []int{2, 9}, []int{15, 9}},
+		{cms.SliceReplace, []int{15}, []int{}, []int{}},
+	} {
+		src, tgt := reflect.ValueOf(tc.src), reflect.New(reflect.TypeOf(tc.tgt))
+		tgt.Elem().Set(reflect.ValueOf(tc.tgt))
+		params := newParams(withFlags(tc.flag), withOwnersSimple(c, nil))
+		if err := copySlice(c, params, src, tgt.Elem()); err != nil {
+			t.Errorf("%v: bad: %v", tc.flag, err)
+			continue
+		}
+		testDeepEqual(t.Errorf, tgt.Elem().Interface(), tc.expect)
+	}
+
+	// merge the elements recursively
+	maps := []map[string]int{{"a": 1}, {"c": 3}}
+	if err := New().CopyTo([]map[string]int{{"b": 2}}, &maps, WithStrategies(cms.SliceMergeByIndex)); err != nil {
+		t.Fatal(err)
+	}
+	testDeepEqual(t.Errorf, maps, []map[string]int{{"a": 1, "b": 2}, {"c": 3}})
+
+	// dedup by key
+	tgt := []item{{1, "a"}, {2, "b"}}
+	src := []item{{2, "B"}, {3, "c"}, {1, "A"}}
+	if err := New().CopyTo(src, &tgt, WithStrategies(cms.SliceDedup),
+		WithSliceDedupKey(func(elem any) any { return elem.(item).ID })); err != nil { //nolint:forcetypeassert //test
+		t.Fatal(err)
+	}
+	testDeepEqual(t.Errorf, tgt, []item{{1, "A"}, {2, "B"}, {3, "c"}})
+
+	type order struct {
+		Items []item   `copy:",slicededup,dedupkey=ID"`
+		Tags  []string `copy:",sliceprepend"`
+	}
+	o := order{Items: []item{{1, "a"}}, Tags: []string{"x"}}
+	if err := New().CopyTo(order{Items: []item{{1, "A"}, {2, "b"}}, Tags: []string{"y"}}, &o); err != nil {
+		t.Fatal(err)
+	}
+	testDeepEqual(t.Errorf, o, order{Items: []item{{1, "A"}, {2, "b"}}, Tags: []string{"y", "x"}})
+
+	// a missing key field is reported rather than comparing the whole elements
+	type badOrder struct {
+		Items []item `copy:",slicededup,dedupkey=Id"`
+	}
+	b := badOrder{Items: []item{{1, "a"}}}
+	if err := New().CopyTo(badOrder{Items: []item{{1, "A"}}}, &b); !errors.Is(err, ErrNoDedupKey) {
+		t.Fatalf("want ErrNoDedupKey, got %v, %v", err, b)
+	}
+	testDeepEqual(t.Errorf, b, badOrder{Items: []item{{1, "a"}}})
+}
+
 func TestCopyArray(t *testing.T) {
 	// defer dbglog.NewCaptureLog(t).Release()
 
@@ -260,3 +332,28 @@ func TestPointerOfPre(t *testing.T) { //nolint:revive
 	// }
 	// t.Logf("avp = %v", tool.Valfmt(&avp))
 }
+
+// hashIntConverter converts an int to a string like "#1".
+type hashIntConverter struct{}
+
+func (hashIntConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) { //nolint:revive,lll
+	return reflect.ValueOf("#" + strconv.Itoa(int(source.Int()))), nil
+}
+
+func (hashIntConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) {
+	if yes = source.Kind() == reflect.Int && target.Kind() == reflect.String; yes {
+		ctx = &ValueConverterContext{Params: params}
+	}
+	return
+}
+
+func TestCopySlice_elemConverter(t *testing.T) {
+	for _, flag := range []cms.CopyMergeStrategy{cms.SliceCopy, cms.SliceUnion, cms.SlicePrepend} {
+		var tgt []string
+		if err := New().CopyTo([]int{1, 2}, &tgt, WithStrategies(flag),
+			WithValueConverters(hashIntConverter{})); err != nil {
+			t.Fatalf("%v: %v", flag, err)
+		}
+		testDeepEqual(t.Errorf, tgt, []string{"#1", "#2"})
+	}
+}
@@ -0,0 +1,205 @@
+package evendeep
+
+import (
+	"reflect"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/flags"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// funcAdaptable tests if a function should be wrapped into an adapter
+// when its type is different from the target, by WithFuncAdapters or
+// the `adapt` tag of the nearest target field.
+func (params *Params) funcAdaptable() bool {
+	if params == nil {
+		return false
+	}
+	if tags := params.nearestFieldTags(); tags != nil && tags.funcAdapter {
+		return true
+	}
+	return params.controller != nil && params.controller.funcAdapters
+}
+
+// adaptFuncTo returns the function fn as is if it can be assigned to
+// typ, or else its adapter if the function adapters are enabled.
+func (ctx *ValueConverterContext) adaptFuncTo(fn reflect.Value, typ reflect.Type) (reflect.Value, error) {
+	switch {
+	case fn.Type().AssignableTo(typ):
+		return fn, nil
+	case fn.IsNil():
+		return reflect.Zero(typ), nil
+	case ctx == nil || !ctx.Params.funcAdaptable():
+		return fn, nil // and let it fail in setting
+	}
+	return ctx.controller.adaptFunc(ctx.Params, fn, typ)
+}
+
+// adaptFunc wraps the source function fn into a function of typ with
+// reflect.MakeFunc.
+//
+// The adapter converts its arguments to the parameters of fn, calls
+// fn, and converts the results of fn back to the results of typ, all
+// through the converter pipeline with the tags and flags of the field
+// in params, so a `func(int) string` can be adapted to a
+// `func(int64) fmt.Stringer`. Both functions must have the same number
+// of parameters, and typ may drop some trailing results of fn. A
+// trailing error result is matched separately: it receives the error
+// returned by fn, or the error in converting the arguments and
+// results. If typ has no error result, fn must have none too, and the
+// values must be converted safely, see convertSafely.
+func (c *cpController) adaptFunc(params *Params, fn reflect.Value, typ reflect.Type) (ret reflect.Value, err error) {
+	ft := fn.Type()
+	if !params.canAdaptFunc(ft, typ) {
+		err = ErrCannotAdaptFunc.FormatWith(ref.Typfmt(ft), ref.Typfmt(typ))
+		return
+	}
+	fOut, fErr := funcResults(ft)
+	tOut, tErr := funcResults(typ)
+
+	// the adapter is called after the copying, so the field tag is
+	// taken now rather than from params which is reused.
+	tag, _ := params.nearestFieldTag()
+	var fl flags.Flags
+	if params != nil {
+		fl = params.flags
+	}
+	newFuncParams := func() *Params {
+		p := fieldParams(c, nil, tag)
+		p.flags = fl
+		return p
+	}
+
+	dbglog.Log("    adapt function %v to %v", ref.Typfmt(ft), ref.Typfmt(typ))
+	ret = reflect.MakeFunc(typ, func(args []reflect.Value) (results []reflect.Value) {
+		results = make([]reflect.Value, typ.NumOut())
+		for i := range results {
+			results[i] = reflect.Zero(typ.Out(i))
+		}
+		fail := func(e error) []reflect.Value {
+			if !tErr {
+				panic(e) // never happens, it has been checked by canAdaptFunc
+			}
+			results[tOut] = reflect.ValueOf(&e).Elem()
+			return results
+		}
+
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			pt := ft.In(i)
+			if ft.IsVariadic() && i == len(args)-1 {
+				pt = reflect.SliceOf(pt.Elem()) // passed by CallSlice
+			}
+			var e error
+			if in[i], e = c.adaptValue(newFuncParams(), arg, pt); e != nil {
+				return fail(e)
+			}
+		}
+
+		var out []reflect.Value
+		if ft.IsVariadic() {
+			out = fn.CallSlice(in)
+		} else {
+			out = fn.Call(in)
+		}
+		if fErr && !out[fOut].IsNil() {
+			return fail(out[fOut].Interface().(error)) //nolint:errcheck,forcetypeassert //it's an error
+		}
+		for i := 0; i < tOut; i++ {
+			var e error
+			if results[i], e = c.adaptValue(newFuncParams(), out[i], typ.Out(i)); e != nil {
+				return fail(e)
+			}
+		}
+		return
+	})
+	return
+}
+
+// canAdaptFunc tests if a function of type ft can be adapted to typ.
+// If typ has no error result to receive the errors, ft must have no
+// error result, and its parameters and results must be converted
+// safely.
+func (params *Params) canAdaptFunc(ft, typ reflect.Type) bool {
+	fOut, fErr := funcResults(ft)
+	tOut, tErr := funcResults(typ)
+	switch {
+	case ft.NumIn() != typ.NumIn() || ft.IsVariadic() != typ.IsVariadic() || tOut > fOut:
+		return false
+	case tErr:
+		return true
+	case fErr:
+		return false
+	}
+	for i := 0; i < ft.NumIn(); i++ {
+		if !params.convertSafely(typ.In(i), ft.In(i)) {
+			return false
+		}
+	}
+	for i := 0; i < tOut; i++ {
+		if !params.convertSafely(ft.Out(i), typ.Out(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// convertSafely tests if a value of type from can be converted to the
+// type to without any error by adaptValue: it's assignable, or a
+// string to a fmt.Stringer, or a number to another number except in
+// the strict mode, or a function or slice of them, such as a callback.
+func (params *Params) convertSafely(from, to reflect.Type) bool {
+	switch fk, tk := from.Kind(), to.Kind(); {
+	case from.AssignableTo(to):
+		return true
+	case fk == reflect.Func && tk == reflect.Func:
+		return params.canAdaptFunc(from, to)
+	case fk == reflect.Slice && tk == reflect.Slice:
+		return params.convertSafely(from.Elem(), to.Elem())
+	case to == stringerType && fk == reflect.String:
+		return true
+	case isNumberKind(fk) && isNumberKind(tk):
+		return params.numConvMode() != numConvStrict
+	default:
+		return fk == tk && (fk == reflect.Bool || fk == reflect.String)
+	}
+}
+
+// funcResults returns the number of results of a function type except
+// the trailing error, and whether it has the trailing error.
+func funcResults(typ reflect.Type) (n int, hasErr bool) {
+	n = typ.NumOut()
+	if n > 0 && typ.Out(n-1) == errorType {
+		n, hasErr = n-1, true
+	}
+	return
+}
+
+// adaptValue converts an argument or a result of the adapted function
+// to typ. A function is adapted recursively, so a callback which takes
+// a callback works too.
+func (c *cpController) adaptValue(params *Params, val reflect.Value, typ reflect.Type) (ret reflect.Value, err error) {
+	ret = reflect.New(typ).Elem()
+	switch vt := val.Type(); {
+	case vt.AssignableTo(typ):
+		ret.Set(val)
+	case vt.Kind() == reflect.Func && typ.Kind() == reflect.Func:
+		var fn reflect.Value
+		if !val.IsNil() {
+			if fn, err = c.adaptFunc(params, val, typ); err == nil {
+				ret.Set(fn)
+			}
+		}
+	case typ == stringerType && vt.Kind() == reflect.String:
+		ret.Set(reflect.ValueOf(stringValue(val.String())))
+	default:
+		err = c.copyTo(params, val, ret)
+	}
+	return
+}
+
+// stringValue is a fmt.Stringer of a plain string, it's the result of
+// an adapted function returning a string for fmt.Stringer.
+type stringValue string
+
+func (s stringValue) String() string { return string(s) }
@@ -0,0 +1,136 @@
+package evendeep_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+type hooksV1 struct {
+	Name     func(id int) string
+	Validate func(s string) error
+	Each     func(fn func(i int)) int
+	Join     func(sep string, a ...int) string
+}
+
+type hooksV2 struct {
+	Name     func(id int64) fmt.Stringer
+	Validate func(s string) error
+	Each     func(fn func(i int64)) int64
+	Join     func(sep string, a ...int64) (string, error)
+}
+
+func TestFuncAdapters(t *testing.T) {
+	errEmpty := errors.New("empty")
+	src := hooksV1{
+		Name: func(id int) string { return fmt.Sprint("#", id) },
+		Validate: func(s string) error {
+			if s == "" {
+				return errEmpty
+			}
+			return nil
+		},
+		Each: func(fn func(i int)) int {
+			for i := 0; i < 3; i++ {
+				fn(i)
+			}
+			return 3
+		},
+		Join: func(sep string, a ...int) string {
+			return strings.Trim(strings.Join(strings.Fields(fmt.Sprint(a)), sep), "[]")
+		},
+	}
+
+	var tgt hooksV2
+	if err := evendeep.New().CopyTo(src, &tgt, evendeep.WithFuncAdaptersOpt); err != nil {
+		t.Fatal(err)
+	}
+	if s := tgt.Name(7).String(); s != "#7" {
+		t.Fatalf("bad name: %q", s)
+	}
+	if err := tgt.Validate(""); !errors.Is(err, errEmpty) {
+		t.Fatalf("want errEmpty, got %v", err)
+	}
+	var sum int64
+	if n := tgt.Each(func(i int64) { sum += i }); n != 3 || sum != 3 {
+		t.Fatalf("bad each: %v, %v", n, sum)
+	}
+	if s, err := tgt.Join("-", 1, 2, 3); err != nil || s != "1-2-3" {
+		t.Fatalf("bad join: %q, %v", s, err)
+	}
+}
+
+func TestFuncAdapters_Tag(t *testing.T) {
+	type v1 struct {
+		OnSave func(id int) bool
+	}
+	type v2 struct {
+		OnSave func(id uint64) bool `copy:",adapt"`
+	}
+	var saved int
+	var tgt v2
+	if err := evendeep.New().CopyTo(v1{OnSave: func(id int) bool { saved = id; return true }}, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if !tgt.OnSave(9) || saved != 9 {
+		t.Fatalf("bad result: %v", saved)
+	}
+
+	// the arity must match
+	var bad func(a, b int) bool
+	if err := evendeep.New().CopyTo(func(id int) bool { return true }, &bad, evendeep.WithFuncAdaptersOpt); !errors.Is(err, evendeep.ErrCannotAdaptFunc) {
+		t.Fatalf("want ErrCannotAdaptFunc, got %v", err)
+	}
+}
+
+func TestFuncAdapters_unsafe(t *testing.T) {
+	atoi := func(s string) int { return len(s) }
+
+	// a string argument might not be converted to int
+	var bad func(id int) int
+	if err := evendeep.New().CopyTo(atoi, &bad, evendeep.WithFuncAdaptersOpt); !errors.Is(err, evendeep.ErrCannotAdaptFunc) {
+		t.Fatalf("want ErrCannotAdaptFunc, got %v", err)
+	}
+
+	// the error result receives the conversion errors
+	var good func(id int) (int, error)
+	if err := evendeep.New().CopyTo(atoi, &good, evendeep.WithFuncAdaptersOpt); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := good(123); err != nil || n != 3 {
+		t.Fatalf("bad result: %v, %v", n, err)
+	}
+}
+
+func TestFuncAdapters_fieldTags(t *testing.T) {
+	type v1 struct {
+		Put func(b int8) bool
+	}
+	type v2 struct {
+		Put func(b int64) (bool, error) `copy:",adapt,strict"`
+	}
+	put := func(b int8) bool { return b > 0 }
+
+	var tgt v2
+	if err := evendeep.New().CopyTo(v1{Put: put}, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := tgt.Put(1); err != nil || !ok {
+		t.Fatalf("bad result: %v, %v", ok, err)
+	}
+	var ce *evendeep.ConversionError
+	if _, err := tgt.Put(300); !errors.As(err, &ce) {
+		t.Fatalf("want a ConversionError by the strict tag, got %v", err)
+	}
+
+	// a strict narrowing conversion can't be adapted without an error result
+	var narrow struct {
+		Put func(b int64) bool `copy:",adapt,strict"`
+	}
+	if err := evendeep.New().CopyTo(v1{Put: put}, &narrow); !errors.Is(err, evendeep.ErrCannotAdaptFunc) {
+		t.Fatalf("want ErrCannotAdaptFunc, got %v", err)
+	}
+}
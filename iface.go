@@ -0,0 +1,163 @@
+package evendeep
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+)
+
+// DefaultDiscriminator is the key in a source map which holds the
+// name of the concrete type, if the target field has no
+// `copy:",discriminator=..."` tag.
+const DefaultDiscriminator = "type"
+
+// RegisterInterfaceImpl registers a concrete type for the interface
+// type I, with its discriminator value name.
+//
+// When the target is an interface (such as a field, or an element of
+// slice or map) and the source is a map (such as a map[string]any
+// from JSON), the concrete type is resolved by the value of the
+// discriminator key in source map, and a new object of that type
+// will be made and filled. The discriminator key is given by the
+// struct tag of the target field, or DefaultDiscriminator:
+//
+//	type Shape interface{ Area() float64 }
+//	type Drawing struct {
+//	    Main   Shape   `copy:",discriminator=kind"`
+//	    Shapes []Shape `copy:",discriminator=kind"`
+//	}
+//
+//	evendeep.RegisterInterfaceImpl[Shape]("circle", Circle{})
+//	evendeep.RegisterInterfaceImpl[Shape]("rect", &Rect{})
+//
+//	src := map[string]any{"Main": map[string]any{"kind": "circle", "R": 1.0}}
+//	err := evendeep.New().CopyTo(src, &drawing) // drawing.Main is a Circle
+//
+// The impl can be a value or a pointer, a pointer to a new object
+// will be made in the latter case.
+func RegisterInterfaceImpl[I any](name string, impl I) {
+	ityp := reflect.TypeOf((*I)(nil)).Elem()
+	if ityp.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("RegisterInterfaceImpl: %v is not an interface type", ityp))
+	}
+	typ := reflect.TypeOf(any(impl))
+	if typ == nil {
+		panic(fmt.Sprintf("RegisterInterfaceImpl: nil impl for %v (%q)", ityp, name))
+	}
+
+	ifaceImplsLock.Lock()
+	defer ifaceImplsLock.Unlock()
+	if ifaceImpls[ityp] == nil {
+		ifaceImpls[ityp] = make(map[string]reflect.Type)
+	}
+	ifaceImpls[ityp][name] = typ
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	ifaceImpls     = make(map[reflect.Type]map[string]reflect.Type)
+	ifaceImplsLock sync.RWMutex
+)
+
+func lookupInterfaceImpl(ityp reflect.Type, name string) (typ reflect.Type, ok bool) {
+	ifaceImplsLock.RLock()
+	defer ifaceImplsLock.RUnlock()
+	typ, ok = ifaceImpls[ityp][name]
+	return
+}
+
+func hasInterfaceImpls(ityp reflect.Type) bool {
+	ifaceImplsLock.RLock()
+	defer ifaceImplsLock.RUnlock()
+	return len(ifaceImpls[ityp]) > 0
+}
+
+// discriminator returns the discriminator key declared in the tag
+// of the target field.
+func (params *Params) discriminator() string {
+	if tags := params.nearestFieldTags(); tags != nil {
+		return tags.discriminator
+	}
+	return ""
+}
+
+// copyToInterfaceImpl copies a source map to an interface target, the
+// concrete type of target is resolved by the discriminator in source.
+//
+// It returns processed = false if the target isn't an interface with
+// registered impls, or the source isn't a map with the discriminator,
+// so that the caller can go ahead.
+//
+// If discriminator is empty, the one declared in the tag of the target
+// field is used, or else DefaultDiscriminator.
+func (c *cpController) copyToInterfaceImpl(params *Params, from, to reflect.Value, discriminator string) (processed bool, err error) { //nolint:lll
+	for to.Kind() == reflect.Ptr && !to.IsNil() {
+		to = to.Elem()
+	}
+	if to.Kind() != reflect.Interface || !to.CanSet() || !hasInterfaceImpls(to.Type()) {
+		return
+	}
+	for k := from.Kind(); k == reflect.Interface || k == reflect.Ptr; k = from.Kind() {
+		if from.IsNil() {
+			return
+		}
+		from = from.Elem()
+	}
+	if from.Kind() != reflect.Map || from.Type().Key().Kind() != reflect.String {
+		return
+	}
+
+	if discriminator == "" {
+		discriminator = params.discriminator()
+	}
+	if discriminator == "" {
+		discriminator = DefaultDiscriminator
+	}
+	dv := from.MapIndex(reflect.ValueOf(discriminator).Convert(from.Type().Key()))
+	if dv.IsValid() && dv.Kind() == reflect.Interface {
+		dv = dv.Elem()
+	}
+	if !dv.IsValid() {
+		return
+	}
+
+	var name string
+	if dv.Kind() == reflect.String {
+		name = dv.String()
+	} else {
+		name = fmt.Sprint(dv.Interface())
+	}
+
+	processed = true
+	ityp := to.Type()
+	typ, ok := lookupInterfaceImpl(ityp, name)
+	if !ok {
+		err = ErrUnknownInterfaceImpl.FormatWith(ityp, discriminator, name)
+		return
+	}
+	dbglog.Log("    interface %v resolved by %s=%q: %v", ityp, discriminator, name, typ)
+
+	var val reflect.Value
+	if cur := to.Elem(); cur.IsValid() && cur.Type() == typ {
+		val = cur // merge into the existing object
+		if typ.Kind() != reflect.Ptr {
+			val = reflect.New(typ).Elem()
+			val.Set(cur)
+		}
+	} else if typ.Kind() == reflect.Ptr {
+		val = reflect.New(typ.Elem())
+	} else {
+		val = reflect.New(typ).Elem()
+	}
+
+	tgt := val
+	if tgt.Kind() == reflect.Ptr {
+		tgt = tgt.Elem()
+	}
+	if err = c.copyTo(params, from, tgt); err == nil {
+		to.Set(val)
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package evendeep_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+type ifaceShape interface{ Area() float64 }
+
+type ifaceCircle struct{ R float64 }
+
+func (c ifaceCircle) Area() float64 { return 3 * c.R * c.R }
+
+type ifaceRect struct{ W, H float64 }
+
+func (r *ifaceRect) Area() float64 { return r.W * r.H }
+
+type ifaceDrawing struct {
+	Name   string
+	Main   ifaceShape   `copy:",discriminator=kind"`
+	Shapes []ifaceShape `copy:",discriminator=kind"`
+}
+
+func init() { //nolint:gochecknoinits //test
+	evendeep.RegisterInterfaceImpl[ifaceShape]("circle", ifaceCircle{})
+	evendeep.RegisterInterfaceImpl[ifaceShape]("rect", &ifaceRect{})
+}
+
+func TestRegisterInterfaceImpl(t *testing.T) {
+	const payload = `{
+		"Name": "d1",
+		"Main": {"kind": "circle", "R": 2},
+		"Shapes": [{"kind": "rect", "W": 2, "H": 3}, {"kind": "circle", "R": 1}]
+	}`
+	var src map[string]any
+	if err := json.Unmarshal([]byte(payload), &src); err != nil {
+		t.Fatal(err)
+	}
+
+	var tgt ifaceDrawing
+	if err := evendeep.New().CopyTo(src, &tgt); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	t.Logf("tgt: %+v", tgt)
+	if c, ok := tgt.Main.(ifaceCircle); !ok || c.R != 2 {
+		t.Fatalf("bad Main: %#v", tgt.Main)
+	}
+	if len(tgt.Shapes) != 2 {
+		t.Fatalf("bad Shapes: %#v", tgt.Shapes)
+	}
+	if r, ok := tgt.Shapes[0].(*ifaceRect); !ok || r.Area() != 6 {
+		t.Fatalf("bad Shapes[0]: %#v", tgt.Shapes[0])
+	}
+	if c, ok := tgt.Shapes[1].(ifaceCircle); !ok || c.R != 1 {
+		t.Fatalf("bad Shapes[1]: %#v", tgt.Shapes[1])
+	}
+
+	// struct -> struct, the source field holds a map
+	type Src struct {
+		Name string
+		Main any
+	}
+	var tgt2 ifaceDrawing
+	if err := evendeep.New().CopyTo(&Src{Name: "d2", Main: map[string]any{"kind": "rect", "W": 1.0, "H": 5.0}}, &tgt2); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if r, ok := tgt2.Main.(*ifaceRect); !ok || r.Area() != 5 {
+		t.Fatalf("bad Main: %#v", tgt2.Main)
+	}
+
+	// unknown discriminator value
+	var tgt3 ifaceDrawing
+	err := evendeep.New().CopyTo(map[string]any{"Main": map[string]any{"kind": "hexagon"}}, &tgt3)
+	if !errors.Is(err, evendeep.ErrUnknownInterfaceImpl) {
+		t.Fatalf("expecting ErrUnknownInterfaceImpl, but got: %v", err)
+	}
+	t.Logf("err: %v", err)
+}
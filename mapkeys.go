@@ -0,0 +1,220 @@
+package evendeep
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// MapKeyTransform renames or normalizes a string key of the target map
+// in copying a map, such as strings.ToLower.
+//
+// See also WithMapKeyTransform and RegisterMapKeyTransform.
+type MapKeyTransform func(key string) string
+
+// Key collision policies for WithKeyCollision and the `collide=` tag.
+//
+// A collision happens when two source keys are mapped to the same
+// target key, by a MapKeyTransform or by the key type conversion, such
+// as "Name" and "name" in lowercase, or 1 and "1" to a string key.
+const (
+	KeyCollisionError = "error" // report ErrKeyCollision, the default
+	KeyCollisionFirst = "first" // the first one of the sorted source keys wins
+	KeyCollisionLast  = "last"  // the last one of the sorted source keys wins
+)
+
+// RegisterMapKeyTransform registers a MapKeyTransform by name for the
+// `keys=` tag, such as `copy:",keys=lower"`. The builtin transforms
+// are "lower" and "upper", they can be replaced. An unregistered name
+// in the tag is reported as ErrUnknownName.
+func RegisterMapKeyTransform(name string, transform MapKeyTransform) {
+	mapKeyTransformsLock.Lock()
+	defer mapKeyTransformsLock.Unlock()
+	mapKeyTransforms[name] = transform
+}
+
+func lookupMapKeyTransform(name string) (transform MapKeyTransform) {
+	if name == "" {
+		return
+	}
+	mapKeyTransformsLock.RLock()
+	defer mapKeyTransformsLock.RUnlock()
+	return mapKeyTransforms[name]
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	mapKeyTransforms = map[string]MapKeyTransform{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}
+	mapKeyTransformsLock sync.RWMutex
+)
+
+// KeysByNameConverter makes a MapKeyTransform from a NameConverter,
+// the keys are renamed by its ToFieldName, such as "UserName" to
+// "user_name" by a snake-case converter.
+func KeysByNameConverter(nc NameConverter) MapKeyTransform {
+	return func(key string) string {
+		return nc.ToFieldName(&NameConverterContext{}, key)
+	}
+}
+
+// mapKeyTransform returns the key transform and the collision policy
+// by the `keys=` and `collide=` tags of the nearest target field, or
+// by WithMapKeyTransform and WithKeyCollision. It reports
+// ErrUnknownName if the transform named by `keys=` isn't registered.
+func (params *Params) mapKeyTransform() (transform MapKeyTransform, policy string, err error) {
+	if params == nil {
+		return nil, KeyCollisionError, nil
+	}
+	if tags := params.nearestFieldTags(); tags != nil {
+		transform, policy = lookupMapKeyTransform(tags.keyTransform), tags.keyCollision
+		if transform == nil && tags.keyTransform != "" {
+			err = ErrUnknownName.FormatWith("map key transform", tags.keyTransform)
+			return
+		}
+	}
+	if c := params.controller; c != nil {
+		if transform == nil {
+			transform = c.keyTransform
+		}
+		if policy == "" {
+			policy = c.keyCollision
+		}
+	}
+	if policy == "" {
+		policy = KeyCollisionError
+	}
+	return
+}
+
+// transformMapKey applies the key transform to a cloned string key.
+// An unknown transform is ignored here, it has been reported by
+// mapKeysOf.
+func (params *Params) transformMapKey(ck reflect.Value) {
+	if ck.Kind() != reflect.String || !ck.CanSet() {
+		return
+	}
+	if transform, _, _ := params.mapKeyTransform(); transform != nil {
+		if s := transform(ck.String()); s != ck.String() {
+			dbglog.Log("         <KEY> transformed: %q -> %q", ck.String(), s)
+			ck.SetString(s)
+		}
+	}
+}
+
+// mapKeysOf returns the keys of src map to be copied into tgt map.
+//
+// If the keys are transformed or converted to another type, they are
+// sorted, and the ones which collide in the target are resolved by the
+// collision policy. The target keys cloned from them are returned in
+// cloned at the same indices, an invalid one means the key cannot be
+// cloned; cloned is nil if the keys are copied as is.
+func (c *cpController) mapKeysOf(params *Params, src, tgt reflect.Value) (keys, cloned []reflect.Value, err error) {
+	keys = src.MapKeys()
+	transform, policy, err := params.mapKeyTransform()
+	if err != nil || transform == nil && src.Type().Key() == tgt.Type().Key() {
+		return
+	}
+
+	sortMapKeys(keys)
+	result, seen := make([]reflect.Value, 0, len(keys)), make(map[any]int)
+	cloned = make([]reflect.Value, 0, len(keys))
+	for _, key := range keys {
+		ck, e := cloneMapKey(c, params, tgt, key)
+		if e != nil || !ck.Type().Comparable() {
+			result = append(result, key) // let the copier report it
+			cloned = append(cloned, reflect.Value{})
+			continue
+		}
+		k := ck.Interface()
+		if i, ok := seen[k]; ok {
+			dbglog.Log("    key collision: %v and %v -> %v (%v)", ref.Valfmt(&result[i]), ref.Valfmt(&key), k, policy)
+			switch policy {
+			case KeyCollisionFirst:
+			case KeyCollisionLast:
+				result[i], cloned[i] = key, ck
+			default:
+				a, _ := ref.Rskip(result[i], reflect.Interface)
+				b, _ := ref.Rskip(key, reflect.Interface)
+				err = ErrKeyCollision.FormatWith(ref.Valfmt(&a), a.Type(), ref.Valfmt(&b), b.Type(), k)
+				return
+			}
+			continue
+		}
+		seen[k] = len(result)
+		result = append(result, key)
+		cloned = append(cloned, ck)
+	}
+	keys = result
+	return
+}
+
+// sortMapKeys sorts the keys of a map, the numbers and strings are in
+// their natural order, the others are by their printed forms, and
+// then by their kinds.
+func sortMapKeys(keys []reflect.Value) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := ref.Rdecodesimple(keys[i]), ref.Rdecodesimple(keys[j])
+		if a.Kind() == b.Kind() {
+			switch a.Kind() { //nolint:exhaustive //others are by printed forms
+			case reflect.String:
+				return a.String() < b.String()
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return a.Int() < b.Int()
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return a.Uint() < b.Uint()
+			case reflect.Float32, reflect.Float64:
+				return a.Float() < b.Float()
+			}
+		}
+		if sa, sb := fmt.Sprint(keys[i]), fmt.Sprint(keys[j]); sa != sb {
+			return sa < sb
+		}
+		return a.Kind() < b.Kind() // such as 1 and "1"
+	})
+}
+
+// stringKeyed converts the map[any]any nested in a value, such as the
+// ones decoded from YAML, to the string-keyed map type of the target
+// (generally map[string]any), at every nesting level. The value is
+// returned as is if nothing to be converted.
+//
+// It's for a target map with string keys and interface values, whose
+// values would keep the source types otherwise.
+func (c *cpController) stringKeyed(params *Params, v reflect.Value, tgt reflect.Type) (ret reflect.Value, err error) {
+	ret = v
+	if tgt.Key().Kind() != reflect.String || tgt.Elem().Kind() != reflect.Interface {
+		return
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.Interface:
+		nv := reflect.New(reflect.MapOf(tgt.Key(), tgt.Elem())).Elem()
+		if err = c.copyTo(newParams(withOwners(c, params, &v, &nv, &v, &nv)), v, nv); err == nil {
+			ret = nv
+		}
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Interface && !v.IsNil():
+		ns := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			var ev reflect.Value
+			if ev, err = c.stringKeyed(params, v.Index(i), tgt); err != nil {
+				return
+			}
+			if ev.IsValid() && !(ev.Kind() == reflect.Interface && ev.IsNil()) {
+				ns.Index(i).Set(ev)
+			}
+		}
+		ret = ns
+	}
+	return
+}
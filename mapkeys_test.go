@@ -0,0 +1,135 @@
+package evendeep_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+func TestMapKeys_TypeConversion(t *testing.T) {
+	var m1 map[string]string
+	if err := evendeep.New().CopyTo(map[int]string{1: "x", 2: "y"}, &m1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m1, map[string]string{"1": "x", "2": "y"}) {
+		t.Fatalf("bad result: %v", m1)
+	}
+
+	var m2 map[int]string
+	if err := evendeep.New().CopyTo(m1, &m2, evendeep.WithStrategies(cms.MapCopy)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m2, map[int]string{1: "x", 2: "y"}) {
+		t.Fatalf("bad result: %v", m2)
+	}
+
+	// from yaml, the nested maps are string-keyed too
+	yml := map[any]any{
+		"name": "app",
+		"db":   map[any]any{"port": 5432},
+		"tags": []any{map[any]any{1: "one"}, "x"},
+	}
+	var m3 map[string]any
+	if err := evendeep.New().CopyTo(yml, &m3); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name": "app",
+		"db":   map[string]any{"port": 5432},
+		"tags": []any{map[string]any{"1": "one"}, "x"},
+	}
+	if !reflect.DeepEqual(m3, want) {
+		t.Fatalf("bad result:\n got %#v\nwant %#v", m3, want)
+	}
+}
+
+func TestMapKeys_Transform(t *testing.T) {
+	src := map[string]any{"Name": "app", "Port": 80}
+
+	for _, s := range []cms.CopyMergeStrategy{cms.MapCopy, cms.MapMerge} {
+		var m map[string]any
+		err := evendeep.New().CopyTo(src, &m, evendeep.WithStrategies(s), evendeep.WithMapKeyTransform(strings.ToLower))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(m, map[string]any{"name": "app", "port": 80}) {
+			t.Fatalf("%v: bad result: %v", s, m)
+		}
+	}
+
+	// by tag
+	type config struct {
+		Env map[string]string `copy:",keys=upper"`
+	}
+	var cfg config
+	if err := evendeep.New().CopyTo(struct{ Env map[string]string }{map[string]string{"home": "/root"}}, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Env["HOME"] != "/root" || len(cfg.Env) != 1 {
+		t.Fatalf("bad result: %v", cfg.Env)
+	}
+
+	// by a name converter
+	var m map[string]any
+	if err := evendeep.New().CopyTo(src, &m, evendeep.WithMapKeyTransform(evendeep.KeysByNameConverter(prefixer{}))); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, map[string]any{"x_Name": "app", "x_Port": 80}) {
+		t.Fatalf("bad result: %v", m)
+	}
+}
+
+type prefixer struct{}
+
+func (prefixer) ToGoName(ctx *evendeep.NameConverterContext, fieldName string) string {
+	return strings.TrimPrefix(fieldName, "x_")
+}
+
+func (prefixer) ToFieldName(ctx *evendeep.NameConverterContext, goName string) string {
+	return "x_" + goName
+}
+
+func TestMapKeys_Collision(t *testing.T) {
+	src := map[string]int{"A": 1, "a": 2, "b": 3}
+
+	var m map[string]int
+	err := evendeep.New().CopyTo(src, &m, evendeep.WithMapKeyTransform(strings.ToLower))
+	if !errors.Is(err, evendeep.ErrKeyCollision) {
+		t.Fatalf("want ErrKeyCollision, got %v", err)
+	}
+
+	for policy, want := range map[string]int{evendeep.KeyCollisionFirst: 1, evendeep.KeyCollisionLast: 2} {
+		m = nil
+		err = evendeep.New().CopyTo(src, &m, evendeep.WithMapKeyTransform(strings.ToLower), evendeep.WithKeyCollision(policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m["a"] != want || m["b"] != 3 || len(m) != 2 {
+			t.Fatalf("%v: bad result: %v", policy, m)
+		}
+	}
+
+	// a collision by the key type conversion
+	var m2 map[string]string
+	err = evendeep.New().CopyTo(map[any]string{1: "int", "1": "string"}, &m2)
+	if !errors.Is(err, evendeep.ErrKeyCollision) {
+		t.Fatalf("want ErrKeyCollision, got %v, %v", err, m2)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "1 (int) and 1 (string)") {
+		t.Fatalf("want the key types in %q", msg)
+	}
+}
+
+func TestMapKeys_UnknownTransform(t *testing.T) {
+	var tgt struct {
+		M map[string]int `copy:",keys=lowre"`
+	}
+	err := evendeep.New().CopyTo(struct{ M map[string]int }{map[string]int{"A": 1}}, &tgt)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v, %v", err, tgt)
+	}
+}
@@ -0,0 +1,149 @@
+package evendeep
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// Map merge policies for WithMapMergePolicy and the `mapmerge=` tag.
+// They decide the value of a key which exists in both the source and
+// target map, in merging with cms.MapMerge (the default strategy).
+const (
+	MapMergeDeep     = "deep"     // merge the nested maps and structs recursively, the default
+	MapMergeOverride = "override" // the source value replaces the existing one as a whole
+	MapMergeKeep     = "keep"     // the existing value is kept
+)
+
+// MapMergeResolver resolves the conflict of a key which exists in both
+// the source and target map. The returned value is converted to the
+// value type of target map if necessary, it's an error if the value
+// cannot be converted, such as "str" to an int.
+//
+// Returning ErrShouldFallback merges them by MapMergeDeep.
+//
+// For the nested maps, the resolver is invoked for their keys too if
+// it falls back to the deep merging.
+type MapMergeResolver func(key, oldValue, newValue any) (value any, err error)
+
+// RegisterMapMergeResolver registers a MapMergeResolver by name for
+// the `mapmerge=` tag, such as `copy:",mapmerge=sum"`.
+func RegisterMapMergeResolver(name string, resolver MapMergeResolver) {
+	mapMergeResolversLock.Lock()
+	defer mapMergeResolversLock.Unlock()
+	mapMergeResolvers[name] = resolver
+}
+
+func lookupMapMergeResolver(name string) (resolver MapMergeResolver) {
+	if name == "" {
+		return
+	}
+	mapMergeResolversLock.RLock()
+	defer mapMergeResolversLock.RUnlock()
+	return mapMergeResolvers[name]
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	mapMergeResolvers     = map[string]MapMergeResolver{}
+	mapMergeResolversLock sync.RWMutex
+)
+
+// mapMergePolicy returns the merge policy or the resolver by the
+// `mapmerge=` tag of the nearest target field, or by
+// WithMapMergePolicy and WithMapMergeResolver. It reports
+// ErrUnknownName if the policy isn't builtin or registered.
+func (params *Params) mapMergePolicy() (policy string, resolver MapMergeResolver, err error) {
+	if params == nil {
+		return MapMergeDeep, nil, nil
+	}
+	if tags := params.nearestFieldTags(); tags != nil && tags.mapMergePolicy != "" {
+		policy = tags.mapMergePolicy
+	} else if c := params.controller; c != nil {
+		policy, resolver = c.mapMergePolicy, c.mapMergeResolver
+	}
+	switch policy {
+	case MapMergeDeep, MapMergeOverride, MapMergeKeep:
+	case "":
+		policy = MapMergeDeep
+	default:
+		if resolver == nil {
+			if resolver = lookupMapMergeResolver(policy); resolver == nil {
+				err = ErrUnknownName.FormatWith("map merge policy", policy)
+			}
+		}
+	}
+	return
+}
+
+// resolveMapMerge merges the source value into tgt[ck] which exists
+// already, by the merge policy. It returns processed = false to merge
+// them deeply.
+//
+// The value returned by a resolver is converted strictly if the value
+// type of tgt is a bool, number or string, such as "str" can't be an
+// int.
+func (c *cpController) resolveMapMerge(params *Params, tgt, ck, old, value reflect.Value) (processed bool, err error) {
+	policy, resolver, err := params.mapMergePolicy()
+	if err != nil {
+		return true, err
+	}
+	resolved := resolver != nil
+	if resolved {
+		var v any
+		if v, err = resolver(anyOf(ck), anyOf(old), anyOf(value)); err == ErrShouldFallback { //nolint:errorlint //want it exactly
+			return false, nil
+		} else if err != nil {
+			return true, err
+		}
+		policy, value = MapMergeOverride, reflect.ValueOf(v)
+	}
+	dbglog.Log("      <MAP> merge policy for key '%v': %v", ref.Valfmt(&ck), policy)
+
+	switch policy {
+	case MapMergeKeep:
+		return true, nil
+	case MapMergeOverride:
+	default:
+		return false, nil // merge them deeply
+	}
+
+	processed = true
+	typ, vv := tgt.Type().Elem(), elemOf(value)
+	if typ.Kind() == reflect.Interface && vv.IsValid() {
+		typ, value = vv.Type(), vv // an interface can't be copied into
+	}
+	nv := reflect.New(typ).Elem()
+	var scalar bool
+	if resolved && value.IsValid() {
+		var rv reflect.Value
+		if rv, scalar, err = asScalar(anyOf(value), typ); err != nil {
+			return
+		} else if scalar {
+			nv.Set(rv)
+		}
+	}
+	if value.IsValid() && !scalar {
+		if err = c.copyTo(newParams(withOwners(c, params, &value, &nv, &value, &nv)), value, nv); err != nil {
+			return
+		}
+	}
+	trySetMapIndex(c, params, tgt, ck, nv)
+	return
+}
+
+func anyOf(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+func elemOf(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		return v.Elem()
+	}
+	return v
+}
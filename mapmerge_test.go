@@ -0,0 +1,185 @@
+package evendeep_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+func layers() (base, overlay map[string]any) {
+	base = map[string]any{
+		"name": "app",
+		"db":   map[string]any{"host": "localhost", "port": 5432},
+		"log":  map[string]any{"level": "info"},
+		"tags": "a",
+	}
+	overlay = map[string]any{
+		"db":   map[string]any{"port": 6432},
+		"log":  map[string]any{"level": "debug"},
+		"tags": "b",
+		"new":  1,
+	}
+	return
+}
+
+func TestMapMergePolicy(t *testing.T) {
+	cases := []struct {
+		policy string
+		want   map[string]any
+	}{
+		{evendeep.MapMergeDeep, map[string]any{
+			"name": "app",
+			"db":   map[string]any{"host": "localhost", "port": 6432},
+			"log":  map[string]any{"level": "debug"},
+			"tags": "b",
+			"new":  1,
+		}},
+		{evendeep.MapMergeOverride, map[string]any{
+			"name": "app",
+			"db":   map[string]any{"port": 6432},
+			"log":  map[string]any{"level": "debug"},
+			"tags": "b",
+			"new":  1,
+		}},
+		{evendeep.MapMergeKeep, map[string]any{
+			"name": "app",
+			"db":   map[string]any{"host": "localhost", "port": 5432},
+			"log":  map[string]any{"level": "info"},
+			"tags": "a",
+			"new":  1,
+		}},
+	}
+	for _, tc := range cases {
+		base, overlay := layers()
+		if err := evendeep.New().CopyTo(overlay, &base, evendeep.WithMapMergePolicy(tc.policy)); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(base, tc.want) {
+			t.Fatalf("%v: bad result:\n got %v\nwant %v", tc.policy, base, tc.want)
+		}
+	}
+}
+
+func TestMapMergePolicy_anotherType(t *testing.T) {
+	// a string replaces a map in a tree from YAML/JSON
+	base := map[string]any{"log": map[string]any{"level": "info"}, "n": 1}
+	overlay := map[string]any{"log": "stdout", "n": "x"}
+	if err := evendeep.New().CopyTo(overlay, &base, evendeep.WithMapMergePolicy(evendeep.MapMergeOverride)); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"log": "stdout", "n": "x"}; !reflect.DeepEqual(base, want) {
+		t.Fatalf("bad result:\n got %v\nwant %v", base, want)
+	}
+}
+
+func TestMapMergePolicy_Tag(t *testing.T) {
+	type config struct {
+		Deep map[string]any
+		Flat map[string]any `copy:",mapmerge=override"`
+		Keep map[string]int `copy:",mapmerge=keep"`
+	}
+	base := config{
+		Deep: map[string]any{"db": map[string]any{"host": "h", "port": 1}},
+		Flat: map[string]any{"db": map[string]any{"host": "h", "port": 1}},
+		Keep: map[string]int{"a": 1},
+	}
+	overlay := config{
+		Deep: map[string]any{"db": map[string]any{"port": 2}},
+		Flat: map[string]any{"db": map[string]any{"port": 2}},
+		Keep: map[string]int{"a": 2, "b": 2},
+	}
+	if err := evendeep.New().CopyTo(overlay, &base); err != nil {
+		t.Fatal(err)
+	}
+	want := config{
+		Deep: map[string]any{"db": map[string]any{"host": "h", "port": 2}},
+		Flat: map[string]any{"db": map[string]any{"port": 2}},
+		Keep: map[string]int{"a": 1, "b": 2},
+	}
+	if !reflect.DeepEqual(base, want) {
+		t.Fatalf("bad result:\n got %+v\nwant %+v", base, want)
+	}
+}
+
+func TestMapMergeResolver(t *testing.T) {
+	var keys []any
+	sum := func(key, oldValue, newValue any) (any, error) {
+		keys = append(keys, key)
+		a, ok1 := oldValue.(int)
+		b, ok2 := newValue.(int)
+		if !ok1 || !ok2 {
+			return nil, evendeep.ErrShouldFallback
+		}
+		return a + b, nil
+	}
+
+	base := map[string]any{"hits": 1, "sub": map[string]any{"hits": 10, "name": "x"}}
+	overlay := map[string]any{"hits": 2, "sub": map[string]any{"hits": 20, "name": "y"}}
+	if err := evendeep.New().CopyTo(overlay, &base, evendeep.WithMapMergeResolver(sum)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"hits": 3, "sub": map[string]any{"hits": 30, "name": "y"}}
+	if !reflect.DeepEqual(base, want) {
+		t.Fatalf("bad result:\n got %v\nwant %v", base, want)
+	}
+	if len(keys) != 4 {
+		t.Fatalf("bad keys: %v", keys)
+	}
+
+	// registered by name
+	evendeep.RegisterMapMergeResolver("sum", sum)
+	type counters struct {
+		Hits map[string]int `copy:",mapmerge=sum"`
+	}
+	c := counters{Hits: map[string]int{"a": 1}}
+	if err := evendeep.New().CopyTo(counters{Hits: map[string]int{"a": 2}}, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Hits["a"] != 3 {
+		t.Fatalf("bad result: %v", c.Hits)
+	}
+
+	// an error breaks the merging
+	errConflict := errors.New("conflict")
+	base = map[string]any{"a": 1}
+	err := evendeep.New().CopyTo(map[string]any{"a": 2}, &base,
+		evendeep.WithMapMergeResolver(func(key, oldValue, newValue any) (any, error) { return nil, errConflict }))
+	if !errors.Is(err, errConflict) || base["a"] != 1 {
+		t.Fatalf("want errConflict, got %v, %v", err, base)
+	}
+}
+
+func TestMapMergeResolver_badResult(t *testing.T) {
+	m := map[string]int{"a": 1}
+	err := evendeep.New().CopyTo(map[string]int{"a": 2}, &m,
+		evendeep.WithMapMergeResolver(func(key, oldValue, newValue any) (any, error) { return "str", nil }))
+	if err == nil || m["a"] != 1 {
+		t.Fatalf("want an error, got %v, %v", err, m)
+	}
+
+	// a number in string is converted still
+	err = evendeep.New().CopyTo(map[string]int{"a": 2}, &m,
+		evendeep.WithMapMergeResolver(func(key, oldValue, newValue any) (any, error) { return "12", nil }))
+	if err != nil || m["a"] != 12 {
+		t.Fatalf("bad result: %v, %v", err, m)
+	}
+}
+
+func TestMapMergePolicy_unknown(t *testing.T) {
+	type config struct {
+		Env map[string]string `copy:",mapmerge=overide"`
+	}
+	c := config{Env: map[string]string{"a": "1"}}
+	err := evendeep.New().CopyTo(config{Env: map[string]string{"a": "2"}}, &c)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v, %v", err, c)
+	}
+
+	m := map[string]string{"a": "1"}
+	err = evendeep.New(evendeep.WithMapMergePolicy("overide")).CopyTo(map[string]string{"a": "2"}, &m)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v, %v", err, m)
+	}
+}
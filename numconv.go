@@ -0,0 +1,271 @@
+package evendeep
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/flags"
+	"github.com/hedzr/evendeep/flags/cms"
+)
+
+// ConversionError is returned for a lossy numeric conversion in the
+// strict mode, see WithStrictConversions and the `copy:",strict"`
+// tag.
+//
+// Err is one of ErrOverflow, ErrTruncated, ErrPrecisionLost,
+// ErrNegativeToUnsigned and ErrBadSyntax, so you can test it by
+// errors.Is:
+//
+//	err := evendeep.New(evendeep.WithStrictConversions()).CopyTo(src, &tgt)
+//	if errors.Is(err, evendeep.ErrOverflow) { ... }
+type ConversionError struct {
+	Value  any          // the source value
+	Target reflect.Type // the target type
+	Err    error        // the reason
+}
+
+func (e *ConversionError) Error() string {
+	if s, ok := e.Value.(string); ok {
+		return fmt.Sprintf("cannot convert %q (string) to %v: %v", s, e.Target, e.Err)
+	}
+	return fmt.Sprintf("cannot convert %v (%T) to %v: %v", e.Value, e.Value, e.Target, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error { return e.Err }
+
+// numConvMode tells how a narrowing numeric conversion is done.
+type numConvMode int
+
+const (
+	numConvWrap     numConvMode = iota // wraps around as Go does
+	numConvStrict                      // reports a ConversionError
+	numConvSaturate                    // clamps to the range of target
+)
+
+// numConvMode returns the numeric conversion mode. The mode of the
+// controller (and params) is resolved at first, and the tag of the
+// nearest target field, one of `wrap`, `strict` and `saturate`,
+// overrides it in either direction.
+func (params *Params) numConvMode() (mode numConvMode) {
+	if params == nil {
+		return
+	}
+	if params.controller != nil {
+		mode = numConvModeOf(params.controller.flags, mode)
+	}
+	mode = numConvModeOf(params.flags, mode)
+	if tags := params.nearestFieldTags(); tags != nil {
+		mode = numConvModeOf(tags.flags, mode)
+	}
+	return
+}
+
+// numConvModeOf returns the numeric conversion mode set in flags, or
+// def if none of them is set.
+func numConvModeOf(f flags.Flags, def numConvMode) numConvMode {
+	switch {
+	case f.IsFlagOK(cms.Saturate):
+		return numConvSaturate
+	case f.IsFlagOK(cms.Strict):
+		return numConvStrict
+	case f.IsFlagOK(cms.Wrap):
+		return numConvWrap
+	}
+	return def
+}
+
+func isIntKind(k reflect.Kind) bool { return k >= reflect.Int && k <= reflect.Int64 }
+
+func isUintKind(k reflect.Kind) bool { return k >= reflect.Uint && k <= reflect.Uintptr }
+
+func isFloatKind(k reflect.Kind) bool { return k == reflect.Float32 || k == reflect.Float64 }
+
+func isNumberKind(k reflect.Kind) bool { return isIntKind(k) || isUintKind(k) || isFloatKind(k) }
+
+// convertNumber converts v to typ like v.Convert(typ), but checks the
+// lossy conversions between integers and floats in the strict and
+// saturating modes. The other kinds are converted by v.Convert(typ).
+//
+//nolint:gocognit,revive //keep it
+func convertNumber(v reflect.Value, typ reflect.Type, mode numConvMode) (ret reflect.Value, err error) {
+	sk, tk := v.Kind(), typ.Kind()
+	if mode == numConvWrap || !isNumberKind(sk) || !isNumberKind(tk) {
+		return v.Convert(typ), nil
+	}
+
+	fail := func(reason error) {
+		var value any = v.String()
+		if v.CanInterface() {
+			value = v.Interface()
+		}
+		err = &ConversionError{Value: value, Target: typ, Err: reason}
+	}
+	ret = reflect.New(typ).Elem()
+
+	if isFloatKind(tk) {
+		var f float64
+		var lost bool
+		mantissa := 53
+		if tk == reflect.Float32 {
+			mantissa = 24
+		}
+		switch {
+		case isIntKind(sk):
+			i := v.Int()
+			f = float64(i)
+			u := uint64(i)
+			if i < 0 {
+				u = -u
+			}
+			lost = !fitsMantissa(u, mantissa)
+		case isUintKind(sk):
+			f = float64(v.Uint())
+			lost = !fitsMantissa(v.Uint(), mantissa)
+		default:
+			f = v.Float()
+		}
+		if lost && mode == numConvStrict {
+			fail(ErrPrecisionLost)
+			return
+		}
+		if tk == reflect.Float32 && !math.IsInf(f, 0) && math.Abs(f) > math.MaxFloat32 {
+			if mode == numConvStrict {
+				fail(ErrOverflow)
+				return
+			}
+			f = math.Copysign(math.MaxFloat32, f)
+		}
+		ret.SetFloat(f)
+		return
+	}
+
+	size := typ.Bits()
+	var lo int64
+	var hi uint64
+	if isIntKind(tk) {
+		lo, hi = -1<<(size-1), 1<<(size-1)-1
+	} else {
+		hi = math.MaxUint64 >> (64 - size)
+	}
+
+	var neg bool   // the source is negative
+	var mag uint64 // the magnitude of source
+	var over bool  // the magnitude of source is greater than 2^64
+	switch {
+	case isIntKind(sk):
+		i := v.Int()
+		if neg = i < 0; neg {
+			mag = -uint64(i)
+		} else {
+			mag = uint64(i)
+		}
+	case isUintKind(sk):
+		mag = v.Uint()
+	default:
+		f := v.Float()
+		if math.IsNaN(f) {
+			if mode == numConvStrict {
+				fail(ErrOverflow)
+			}
+			return // zero for saturating
+		}
+		t := math.Trunc(f)
+		if t != f && mode == numConvStrict {
+			fail(ErrTruncated)
+			return
+		}
+		neg, t = t < 0, math.Abs(t)
+		if over = t >= 1<<64; !over {
+			mag = uint64(t)
+		}
+	}
+
+	switch {
+	case neg && mag != 0 && isUintKind(tk):
+		if mode == numConvStrict {
+			fail(ErrNegativeToUnsigned)
+		}
+		return // zero for saturating
+	case neg && (over || mag > uint64(-lo)):
+		if mode == numConvStrict {
+			fail(ErrOverflow)
+			return
+		}
+		ret.SetInt(lo)
+	case !neg && (over || mag > hi):
+		if mode == numConvStrict {
+			fail(ErrOverflow)
+			return
+		}
+		if isIntKind(tk) {
+			ret.SetInt(int64(hi))
+		} else {
+			ret.SetUint(hi)
+		}
+	case isUintKind(tk):
+		ret.SetUint(mag)
+	case neg:
+		ret.SetInt(-int64(mag))
+	default:
+		ret.SetInt(int64(mag))
+	}
+	return
+}
+
+// parseNumber parses a string to an integer or a float target in the
+// strict or saturating mode. In the strict mode, a string which
+// isn't a number at all, or has trailing garbage (such as "12abc"),
+// causes a ConversionError with ErrBadSyntax.
+func parseNumber(str string, typ reflect.Type, mode numConvMode) (ret reflect.Value, err error) {
+	s := strings.TrimSpace(str)
+	if s == "" {
+		return reflect.Zero(typ), nil
+	}
+
+	var v reflect.Value
+	if !isFloatKind(typ.Kind()) {
+		if i, e := strconv.ParseInt(s, 10, 64); e == nil {
+			v = reflect.ValueOf(i)
+		} else if u, e := strconv.ParseUint(s, 10, 64); e == nil {
+			v = reflect.ValueOf(u)
+		}
+	}
+	if !v.IsValid() {
+		f, e := strconv.ParseFloat(s, 64)
+		switch {
+		case e == nil: // truncated toward zero by convertNumber, as a float is
+		case isRangeError(e) && mode == numConvSaturate:
+			f = math.Copysign(math.MaxFloat64, f)
+		case isRangeError(e):
+			return ret, &ConversionError{Value: str, Target: typ, Err: ErrOverflow}
+		case mode == numConvStrict:
+			return ret, &ConversionError{Value: str, Target: typ, Err: ErrBadSyntax}
+		default:
+			return ret, e
+		}
+		v = reflect.ValueOf(f)
+	}
+	return convertNumber(v, typ, mode)
+}
+
+// fitsMantissa tests if an integer magnitude u can be represented
+// exactly by a float with the given mantissa bits.
+func fitsMantissa(u uint64, mantissa int) bool {
+	return u == 0 || bits.Len64(u)-bits.TrailingZeros64(u) <= mantissa
+}
+
+func isConversionError(err error) bool {
+	var ce *ConversionError
+	return errors.As(err, &ce)
+}
+
+func isRangeError(err error) bool {
+	ne, ok := err.(*strconv.NumError) //nolint:errorlint //strconv returns it directly
+	return ok && ne.Err == strconv.ErrRange
+}
@@ -0,0 +1,138 @@
+package evendeep_test
+
+import (
+	"math"
+	"testing"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+type numconvSource struct {
+	I8  int64
+	U   int64
+	I   float64
+	F32 uint64
+	S   string
+}
+
+type numconvTarget struct {
+	I8  int8
+	U   uint
+	I   int
+	F32 float32
+	S   int32
+}
+
+func TestWithStrictConversions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  numconvSource
+		want error
+	}{
+		{"ok", numconvSource{I8: 100, U: 1, I: 2, F32: 1 << 30, S: " 42 "}, nil},
+		{"overflow", numconvSource{I8: 300}, evendeep.ErrOverflow},
+		{"negative", numconvSource{U: -1}, evendeep.ErrNegativeToUnsigned},
+		{"truncated", numconvSource{I: 2.7}, evendeep.ErrTruncated},
+		{"precision", numconvSource{F32: 1<<24 + 1}, evendeep.ErrPrecisionLost},
+		{"syntax", numconvSource{S: "12abc"}, evendeep.ErrBadSyntax},
+		{"string overflow", numconvSource{S: "3000000000"}, evendeep.ErrOverflow},
+		{"string truncated", numconvSource{S: "1.5"}, evendeep.ErrTruncated},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var tgt numconvTarget
+			err := evendeep.New(evendeep.WithStrictConversions()).CopyTo(tc.src, &tgt)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if tgt.I8 != 100 || tgt.U != 1 || tgt.I != 2 || tgt.F32 != 1<<30 || tgt.S != 42 {
+					t.Fatalf("bad result: %+v", tgt)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("want %v, got %v", tc.want, err)
+			}
+			var ce *evendeep.ConversionError
+			if !errors.As(err, &ce) || ce.Target == nil {
+				t.Fatalf("want a *ConversionError, got %v", err)
+			}
+			t.Logf("%v", ce)
+		})
+	}
+}
+
+func TestWithSaturatingConversions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		src  numconvSource
+		want numconvTarget
+	}{
+		{"max", numconvSource{I8: 300, I: 1e30, S: "3000000000"}, numconvTarget{I8: math.MaxInt8, I: math.MaxInt, S: math.MaxInt32}},
+		{"min", numconvSource{I8: -300, U: -1, I: -1e30, S: "-1e20"}, numconvTarget{I8: math.MinInt8, I: math.MinInt, S: math.MinInt32}},
+		{"truncated", numconvSource{I: -2.7, S: "2.5"}, numconvTarget{I: -2, S: 2}},
+		{"truncated negative", numconvSource{I: 2.5, S: "-2.7"}, numconvTarget{I: 2, S: -2}},
+		{"nan", numconvSource{I: math.NaN()}, numconvTarget{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var tgt numconvTarget
+			if err := evendeep.New(evendeep.WithSaturatingConversions()).CopyTo(tc.src, &tgt); err != nil {
+				t.Fatal(err)
+			}
+			if tgt != tc.want {
+				t.Fatalf("want %+v, got %+v", tc.want, tgt)
+			}
+		})
+	}
+}
+
+func TestNumericConversionTags(t *testing.T) {
+	type target struct {
+		Wrap   int8
+		Strict int8 `copy:",strict"`
+		Clamp  int8 `copy:",saturate"`
+	}
+
+	var tgt target
+	err := evendeep.New().CopyTo(map[string]any{"Wrap": 300, "Clamp": 300}, &tgt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tgt.Wrap != 44 || tgt.Clamp != math.MaxInt8 {
+		t.Fatalf("bad result: %+v", tgt)
+	}
+
+	type source struct{ Wrap, Strict, Clamp int64 }
+	err = evendeep.New().CopyTo(source{Strict: 300}, &tgt)
+	if !errors.Is(err, evendeep.ErrOverflow) {
+		t.Fatalf("want ErrOverflow, got %v", err)
+	}
+
+	// a field tag overrides the controller mode in either direction
+	type override struct {
+		Wrap   int8 `copy:",wrap"`
+		Strict int8 `copy:",strict"`
+		Clamp  int8 `copy:",saturate"`
+	}
+	var o override
+	err = evendeep.New(evendeep.WithSaturatingConversions()).CopyTo(source{Strict: 300}, &o)
+	if !errors.Is(err, evendeep.ErrOverflow) {
+		t.Fatalf("want ErrOverflow by the strict tag, got %v (%+v)", err, o)
+	}
+	o = override{}
+	err = evendeep.New(evendeep.WithStrictConversions()).CopyTo(source{Wrap: 300, Clamp: 300}, &o)
+	if err != nil || o.Wrap != 44 || o.Clamp != math.MaxInt8 {
+		t.Fatalf("want the wrap and saturate tags applied, got %v (%+v)", err, o)
+	}
+
+	// the elements of a slice field share the tags of the field
+	var list struct {
+		List []uint8 `copy:",strict"`
+	}
+	err = evendeep.New().CopyTo(map[string]any{"List": []int{1, -2}}, &list)
+	if !errors.Is(err, evendeep.ErrNegativeToUnsigned) {
+		t.Fatalf("want ErrNegativeToUnsigned, got %v (%v)", err, list)
+	}
+}
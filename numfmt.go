@@ -0,0 +1,161 @@
+package evendeep
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NumberFormat describes how a human-written number string is parsed
+// when it's being copied to an integer or a float target, such as
+// "1,234.56", "1.234,56", "12%", "5k" and "1.5MiB".
+//
+// A NumberFormat is registered by name with RegisterNumberFormat, and
+// selected by WithNumberFormat or by the struct tag of target field:
+//
+//	type Limits struct {
+//	    MaxBody  int64   `copy:",parse=bytesize"` // "1.5MiB" -> 1572864
+//	    Ratio    float64 `copy:",parse=human"`    // "12%" -> 0.12
+//	    Price    float64 `copy:",parse=eu"`       // "1.234,56" -> 1234.56
+//	}
+//
+// The builtin formats are:
+//
+//	human      "1,234.56", "1_000", "12%", "5k", "1.5MiB", "2e3"
+//	eu         "1.234,56", "1 234,56", "12,5%"
+//	bytesize   "512", "5k", "5KB", "1.5MiB", "2GiB"
+//	percent    "12%", "12.5 %"
+type NumberFormat struct {
+	Grouping    string // the grouping separators to be removed, such as "," or ". "
+	Decimal     rune   // the decimal mark, '.' if it's zero
+	Underscores bool   // allows "1_000_000"
+	Percent     bool   // "12%" is 0.12
+	SI          bool   // the decimal suffixes: k, M, G, T, P, E (and with "B")
+	IEC         bool   // the binary suffixes: Ki, Mi, Gi, Ti, Pi, Ei (and with "B")
+}
+
+// RegisterNumberFormat registers a NumberFormat by name, a builtin
+// format can be replaced.
+func RegisterNumberFormat(name string, format NumberFormat) {
+	numberFormatsLock.Lock()
+	defer numberFormatsLock.Unlock()
+	numberFormats[name] = &format
+}
+
+func lookupNumberFormat(name string) (format *NumberFormat) {
+	if name == "" {
+		return
+	}
+	numberFormatsLock.RLock()
+	defer numberFormatsLock.RUnlock()
+	return numberFormats[name]
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	numberFormats = map[string]*NumberFormat{
+		"human":    {Grouping: ",", Underscores: true, Percent: true, SI: true, IEC: true},
+		"eu":       {Grouping: ". \u00a0", Decimal: ',', Percent: true},
+		"bytesize": {Grouping: ",", Underscores: true, SI: true, IEC: true},
+		"percent":  {Percent: true},
+	}
+	numberFormatsLock sync.RWMutex
+
+	siSuffixes = []struct {
+		suffix string
+		exp    int
+	}{{"k", 1}, {"K", 1}, {"M", 2}, {"G", 3}, {"T", 4}, {"P", 5}, {"E", 6}}
+	iecSuffixes = []struct {
+		suffix string
+		exp    int
+	}{{"Ki", 1}, {"Mi", 2}, {"Gi", 3}, {"Ti", 4}, {"Pi", 5}, {"Ei", 6}}
+)
+
+// Normalize converts a human-written number string to the plain form
+// which can be parsed by strconv, such as "1.5MiB" to "1572864", "12%"
+// to "0.12". It returns ok = false if s isn't a number in this format.
+func (f *NumberFormat) Normalize(s string) (plain string, ok bool) {
+	s = strings.TrimSpace(s)
+	mul := big.NewRat(1, 1)
+
+	if f.Percent {
+		if t, found := strings.CutSuffix(s, "%"); found {
+			s = strings.TrimSpace(t)
+			mul.SetFrac64(1, 100) //nolint:gomnd //percent
+		}
+	}
+	if f.SI || f.IEC {
+		s = f.cutSuffix(s, mul)
+	}
+
+	if f.Underscores {
+		s = strings.ReplaceAll(s, "_", "")
+	}
+	for _, sep := range f.Grouping {
+		s = strings.ReplaceAll(s, string(sep), "")
+	}
+	if f.Decimal != 0 && f.Decimal != '.' {
+		if strings.ContainsRune(s, '.') {
+			return // a '.' should be a grouping separator
+		}
+		s = strings.ReplaceAll(s, string(f.Decimal), ".")
+	}
+
+	r, valid := new(big.Rat).SetString(s)
+	if !valid {
+		return
+	}
+	r.Mul(r, mul)
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+	v, _ := r.Float64()
+	return strconv.FormatFloat(v, 'g', -1, 64), true
+}
+
+// cutSuffix removes a SI or IEC suffix and multiplies mul with it.
+func (f *NumberFormat) cutSuffix(s string, mul *big.Rat) string {
+	t, hasB := strings.CutSuffix(s, "B")
+	if f.IEC {
+		for _, it := range iecSuffixes {
+			if n, found := strings.CutSuffix(t, it.suffix); found {
+				mul.Mul(mul, new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), uint(10*it.exp)))) //nolint:gomnd //1024^exp
+				return strings.TrimSpace(n)
+			}
+		}
+	}
+	if f.SI {
+		for _, it := range siSuffixes {
+			if n, found := strings.CutSuffix(t, it.suffix); found && n != "" {
+				mul.Mul(mul, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(1000), big.NewInt(int64(it.exp)), nil))) //nolint:gomnd,lll //1000^exp
+				return strings.TrimSpace(n)
+			}
+		}
+	}
+	if hasB {
+		return strings.TrimSpace(t)
+	}
+	return s
+}
+
+// numberFormat returns the NumberFormat declared by the tag of the
+// nearest target field, or by WithNumberFormat. It reports
+// ErrUnknownName if the format isn't registered.
+func (params *Params) numberFormat() (format *NumberFormat, err error) {
+	if params == nil {
+		return
+	}
+	name := ""
+	if tags := params.nearestFieldTags(); tags != nil && tags.numberFormat != "" {
+		name = tags.numberFormat
+	} else if params.controller != nil {
+		name = params.controller.numberFormat
+	}
+	if name != "" {
+		if format = lookupNumberFormat(name); format == nil {
+			err = ErrUnknownName.FormatWith("number format", name)
+		}
+	}
+	return
+}
@@ -0,0 +1,101 @@
+package evendeep_test
+
+import (
+	"testing"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestNumberFormat_Normalize(t *testing.T) {
+	human := evendeep.NumberFormat{Grouping: ",", Underscores: true, Percent: true, SI: true, IEC: true}
+	eu := evendeep.NumberFormat{Grouping: ". ", Decimal: ',', Percent: true}
+
+	for i, c := range []struct {
+		format *evendeep.NumberFormat
+		src    string
+		expect string
+		ok     bool
+	}{
+		{&human, "1,234.56", "1234.56", true},
+		{&human, "1_000_000", "1000000", true},
+		{&human, "12%", "0.12", true},
+		{&human, "5k", "5000", true},
+		{&human, "5 KB", "5000", true},
+		{&human, "1.5MiB", "1572864", true},
+		{&human, "2GiB", "2147483648", true},
+		{&human, "512B", "512", true},
+		{&human, "2e3", "2000", true},
+		{&human, "-1.5k", "-1500", true},
+		{&human, "12abc", "", false},
+		{&eu, "1.234,56", "1234.56", true},
+		{&eu, "1 234,5 %", "12.345", true},
+		{&eu, "1,5", "1.5", true},
+	} {
+		actual, ok := c.format.Normalize(c.src)
+		if actual != c.expect || ok != c.ok {
+			t.Fatalf("%5d. expecting %q (%v), but got %q (%v) | src is %q", i, c.expect, c.ok, actual, ok, c.src)
+		}
+	}
+}
+
+func TestWithNumberFormat(t *testing.T) {
+	type limits struct {
+		MaxBody int64   `copy:",parse=bytesize"`
+		Ratio   float64 `copy:",parse=percent"`
+		Price   float64 `copy:",parse=eu"`
+		Count   int
+	}
+	src := map[string]any{
+		"MaxBody": "1.5MiB",
+		"Ratio":   "12.5%",
+		"Price":   "1.234,56",
+		"Count":   "1,000",
+	}
+
+	var tgt limits
+	if err := evendeep.New().CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if tgt.MaxBody != 1572864 || tgt.Ratio != 0.125 || tgt.Price != 1234.56 || tgt.Count != 0 {
+		t.Fatalf("bad result: %+v", tgt)
+	}
+
+	tgt = limits{}
+	if err := evendeep.New(evendeep.WithNumberFormat("human")).CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if tgt.Count != 1000 {
+		t.Fatalf("bad result: %+v", tgt)
+	}
+
+	evendeep.RegisterNumberFormat("ch", evendeep.NumberFormat{Grouping: "'"})
+	var n struct {
+		N int `copy:",parse=ch"`
+	}
+	err := evendeep.New(evendeep.WithStrictConversions()).CopyTo(map[string]any{"N": "1'000'000"}, &n)
+	if err != nil || n.N != 1000000 {
+		t.Fatalf("bad result: %+v, %v", n, err)
+	}
+	err = evendeep.New(evendeep.WithStrictConversions()).CopyTo(map[string]any{"N": "1,000"}, &n)
+	if !errors.Is(err, evendeep.ErrBadSyntax) {
+		t.Fatalf("want ErrBadSyntax, got %v", err)
+	}
+}
+
+func TestWithNumberFormat_unknown(t *testing.T) {
+	var n struct {
+		N int `copy:",parse=bytesise"`
+	}
+	err := evendeep.New().CopyTo(map[string]any{"N": "1KiB"}, &n)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v", err)
+	}
+
+	var m struct{ N int }
+	err = evendeep.New(evendeep.WithNumberFormat("humen")).CopyTo(map[string]any{"N": "1,000"}, &m)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v", err)
+	}
+}
@@ -33,6 +33,8 @@ type Params struct {
 
 	visited           map[visit]visiteddestination
 	visiting          visit
+	aliases           map[aliasKey]reflect.Value // source reference -> target object, in root Params only
+	fieldTag          reflect.StructTag          // the tag of the field being filled by a map, see fromMapConverter.toStruct
 	resultForNewSlice *reflect.Value
 
 	targetIterator structIterable //
@@ -134,6 +136,8 @@ func withOwners(c *cpController, ownerParams *Params, ownerSource, ownerTarget,
 			p.targetIterator = newStructIterator(t,
 				withStructPtrAutoExpand(c.autoExpandStruct),
 				withStructFieldPtrAutoNew(c.autoNewStruct),
+				withStructPtrKept(c.preserveAliasing),
+				withStructKept(c.isKeptStructType),
 				withStructSource(p.srcDecoded, c.autoExpandStruct),
 			)
 		}
@@ -374,6 +378,33 @@ func (params *Params) parseFieldTags(tag reflect.StructTag) (flagsInTag *fieldTa
 	return
 }
 
+// nearestFieldTags returns the tags of the nearest target struct
+// field, the elements of a slice or map field share its tags.
+func (params *Params) nearestFieldTags() (tags *fieldTags) {
+	if tag, ok := params.nearestFieldTag(); ok {
+		tags, _ = params.parseFieldTags(tag)
+	}
+	return
+}
+
+// nearestFieldTag returns the raw tag of the nearest field, see
+// nearestFieldTags.
+func (params *Params) nearestFieldTag() (tag reflect.StructTag, ok bool) {
+	for p := params; p != nil; p = p.owner {
+		if p.fieldTag != "" {
+			return p.fieldTag, true
+		}
+		if p.accessor == nil {
+			continue
+		}
+		if sf := p.accessor.StructField(); sf != nil {
+			return sf.Tag, true
+		}
+		break
+	}
+	return
+}
+
 func (params *Params) isFlagExists(ftf cms.CopyMergeStrategy) (ret bool) {
 	if params == nil {
 		return
@@ -348,18 +348,131 @@ func ValfmtPure(v *reflect.Value) string {
 		res := v.MethodByName("String").Call(nil)
 		return res[0].String()
 	}
-	if IsNumericKind(v.Kind()) {
-		return fmt.Sprintf("%v", v.Interface())
+	if k := v.Kind(); IsNumericKind(k) {
+		switch { // an unexported field can't be interfaced
+		case v.CanInterface():
+			return fmt.Sprintf("%v", v.Interface())
+		case IsNumSIntegerKind(k):
+			return fmt.Sprintf("%v", v.Int())
+		case IsNumUIntegerKind(k) || k == reflect.Uintptr:
+			return fmt.Sprintf("%v", v.Uint())
+		case IsNumFloatKind(k):
+			return fmt.Sprintf("%v", v.Float())
+		default:
+			return fmt.Sprintf("%v", v.Complex())
+		}
 	}
 	if CanConvert(v, StringType) {
 		return v.Convert(StringType).String()
 	}
 	if v.CanInterface() {
+		if canRefer(v.Type(), true) {
+			switch cyclic, large := isCyclic(*v); { // fmt would recurse forever
+			case cyclic:
+				return fmt.Sprintf("<cyclic %v>", v.Type())
+			case large:
+				return fmt.Sprintf("<large %v>", v.Type())
+			}
+		}
 		return fmt.Sprintf("%v", v.Interface())
 	}
 	return fmt.Sprintf("<%v>", v.Kind())
 }
 
+// maxCycleProbes bounds the values isCyclic visits. A larger value
+// isn't checked and is formatted by its type only, since its string
+// would be truncated to maxValueStringLen anyway.
+const maxCycleProbes = 256
+
+type cycleKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// canRefer tests if fmt may recurse through a value of typ back to
+// itself, that is, typ holds a map, slice or interface. A pointer is
+// followed by fmt at the top level only, deeper ones are printed as
+// the addresses.
+func canRefer(typ reflect.Type, top bool) bool {
+	switch typ.Kind() { //nolint:exhaustive //others can't refer to anything
+	case reflect.Map, reflect.Slice, reflect.Interface:
+		return true
+	case reflect.Ptr:
+		return top && canRefer(typ.Elem(), false)
+	case reflect.Array:
+		return canRefer(typ.Elem(), false)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			if canRefer(typ.Field(i).Type, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCyclic tests if v refers to itself through the maps, slices and
+// interfaces, such as a slice s with s[0] = s. It visits up to
+// maxCycleProbes values and skips the ones which can't refer to
+// anything, so it's cheap for the formatter; large is true if v has
+// more values than that.
+func isCyclic(v reflect.Value) (cyclic, large bool) {
+	p := cycleProbe{budget: maxCycleProbes}
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem() // fmt prints &{...} for a top-level pointer
+	}
+	cyclic = p.probe(v)
+	return cyclic && p.budget >= 0, p.budget < 0
+}
+
+type cycleProbe struct {
+	visiting map[cycleKey]bool
+	budget   int
+}
+
+func (p *cycleProbe) probe(v reflect.Value) (yes bool) {
+	if !v.IsValid() || !canRefer(v.Type(), false) {
+		return
+	}
+	if p.budget--; p.budget < 0 {
+		return true
+	}
+
+	switch v.Kind() { //nolint:exhaustive //others can't refer to anything
+	case reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		key := cycleKey{v.Pointer(), v.Type()}
+		if p.visiting == nil {
+			p.visiting = make(map[cycleKey]bool)
+		}
+		if p.visiting[key] {
+			return true
+		}
+		p.visiting[key] = true
+		defer delete(p.visiting, key)
+	}
+
+	switch v.Kind() { //nolint:exhaustive //others can't refer to anything
+	case reflect.Interface:
+		return !v.IsNil() && p.probe(v.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len() && !yes; i++ {
+			yes = p.probe(v.Index(i))
+		}
+	case reflect.Map:
+		for it := v.MapRange(); it.Next() && !yes; {
+			yes = p.probe(it.Value())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField() && !yes; i++ {
+			yes = p.probe(v.Field(i))
+		}
+	}
+	return
+}
+
 func Iserrortype(typ reflect.Type) bool {
 	return typ.Implements(errtyp)
 }
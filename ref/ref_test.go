@@ -472,3 +472,26 @@ type Employee struct {
 	Bool2 bool
 	Ro    []int
 }
+
+func TestValfmtCyclic(t *testing.T) {
+	ss := make([]any, 2)
+	ss[0], ss[1] = ss, "x"
+	large := make([]any, 300)
+	for i := range large {
+		large[i] = []int{i}
+	}
+	for _, tc := range []struct {
+		v      any
+		expect string
+	}{
+		{ss, "<cyclic []interface {}>"},
+		{large, "<large []interface {}>"},
+		{[]any{1, []int{2}}, "[1 [2]]"},
+		{make([]int, 300)[:3], "[0 0 0]"},
+	} {
+		v := reflect.ValueOf(tc.v)
+		if got := ref.Valfmt(&v); got != tc.expect {
+			t.Errorf("expecting %q but got %q", tc.expect, got)
+		}
+	}
+}
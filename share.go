@@ -0,0 +1,69 @@
+package evendeep
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// RegisterImmutableType marks T as an immutable type, a value of T (or
+// a pointer to T) is shared with the target rather than deep-copied,
+// even in cloning. It's useful for the large read-mostly objects, such
+// as a lookup table which is never mutated after built.
+//
+// A struct field can be shared individually by tag `copy:",share"`,
+// see also WithShareBytesAbove.
+func RegisterImmutableType[T any]() {
+	immutableTypes.Store(ref.RindirectType(reflect.TypeOf((*T)(nil)).Elem()), true)
+}
+
+var immutableTypes sync.Map //nolint:gochecknoglobals //i know that
+
+func isImmutableType(typ reflect.Type) bool {
+	_, ok := immutableTypes.Load(ref.RindirectType(typ))
+	return ok
+}
+
+// shareable tests if the source value can be shared with the target
+// as is: it's an immutable type, or the target field is tagged with
+// `share`, or it's a byte slice larger than the threshold of
+// WithShareBytesAbove.
+//
+// A nil or zero source is never shared, it's copied as usual so that
+// the omitting and merging strategies are kept.
+func (c *cpController) shareable(params *Params, from, to reflect.Value) (yes bool) {
+	typ := from.Type()
+	if typ != to.Type() || !to.CanSet() || ref.IsNil(from) || ref.IsZero(from) {
+		return
+	}
+	switch {
+	case isImmutableType(typ):
+		yes = true
+	case c.shareBytesAbove > 0 && typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8:
+		yes = from.Len() >= c.shareBytesAbove
+	default:
+		yes = params.isSharedField(typ)
+	}
+	if yes {
+		dbglog.Log("    share %v with the target", ref.Typfmt(typ))
+	}
+	return
+}
+
+// isSharedField tests if the value of typ is the nearest target field
+// which is tagged with `share`.
+func (params *Params) isSharedField(typ reflect.Type) bool {
+	for p := params; p != nil; p = p.owner {
+		if p.accessor == nil {
+			continue
+		}
+		if sf := p.accessor.StructField(); sf != nil && sf.Type == typ {
+			tags, _ := p.parseFieldTags(sf.Tag)
+			return tags.share
+		}
+		break
+	}
+	return false
+}
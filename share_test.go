@@ -0,0 +1,70 @@
+package evendeep_test
+
+import (
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+type lookupTable struct {
+	Codes map[string]int
+}
+
+type snapshot struct {
+	Table  *lookupTable
+	Blob   []byte
+	Small  []byte
+	Shared map[string][]int `copy:",share"`
+	Owned  map[string][]int
+}
+
+func TestShare(t *testing.T) {
+	evendeep.RegisterImmutableType[lookupTable]()
+
+	src := snapshot{
+		Table:  &lookupTable{Codes: map[string]int{"a": 1}},
+		Blob:   make([]byte, 1024),
+		Small:  []byte("hi"),
+		Shared: map[string][]int{"x": {1}},
+		Owned:  map[string][]int{"y": {2}},
+	}
+
+	var tgt snapshot
+	if err := evendeep.New(evendeep.WithShareBytesAbove(512)).CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case tgt.Table != src.Table:
+		t.Fatal("the immutable type should be shared")
+	case &tgt.Blob[0] != &src.Blob[0]:
+		t.Fatal("the large blob should be shared")
+	case &tgt.Small[0] == &src.Small[0]:
+		t.Fatal("the small blob should be copied")
+	case &tgt.Shared["x"][0] != &src.Shared["x"][0]:
+		t.Fatal("the share field should be shared")
+	case &tgt.Owned["y"][0] == &src.Owned["y"][0] || tgt.Owned["y"][0] != 2:
+		t.Fatalf("the owned field should be copied: %v", tgt.Owned)
+	}
+
+	// a clone shares them too
+	clone, ok := evendeep.MakeClone(src).(snapshot)
+	if !ok || clone.Table != src.Table || &clone.Shared["x"][0] != &src.Shared["x"][0] {
+		t.Fatalf("bad clone: %+v", clone)
+	}
+	if &clone.Blob[0] == &src.Blob[0] {
+		t.Fatal("the blob shouldn't be shared without WithShareBytesAbove")
+	}
+}
+
+func TestShare_nilSource(t *testing.T) {
+	evendeep.RegisterImmutableType[lookupTable]()
+
+	table := &lookupTable{Codes: map[string]int{"a": 1}}
+	tgt := snapshot{Table: table, Shared: map[string][]int{"x": {1}}}
+	if err := evendeep.New().CopyTo(snapshot{}, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if tgt.Table != table || len(tgt.Shared["x"]) != 1 {
+		t.Fatalf("the target shouldn't be wiped by a nil source: %+v", tgt)
+	}
+}
@@ -0,0 +1,371 @@
+package evendeep
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep/dbglog"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// WithStdConverters enables the converters for the common types of
+// the standard library, from/to string and to a deep clone of itself:
+//
+//   - net.IP, netip.Addr, netip.AddrPort, netip.Prefix: "10.0.0.1",
+//     "10.0.0.1:80", "10.0.0.0/8"
+//   - url.URL, *url.URL: "https://example.com/a?b=c"
+//   - big.Int, big.Float, big.Rat: "0x1f", "1.5e100", "3/4"
+//   - *regexp.Regexp: "^a+$", a compiled regexp is shared, not copied
+//   - os.FileMode (fs.FileMode): "0644", "drwxr-xr-x"
+//   - time.Month, time.Weekday: "March", "mar", "3"; "Monday", "1"
+//   - [16]byte (UUID-like): "123e4567-e89b-12d3-a456-426614174000"
+//
+// The pointers of these types are supported too. A big.Int, big.Float
+// and big.Rat is cloned with its own words, instead of sharing them.
+// A bad string returns a *ConversionError with ErrBadSyntax.
+func WithStdConverters() Opt {
+	return func(c *cpController) {
+		cvt := &stdTypesConverter{}
+		c.stdConverters = true
+		c.valueConverters = append(c.valueConverters, cvt)
+		c.valueCopiers = append(c.valueCopiers, cvt)
+	}
+}
+
+// stdType tells how a std type T is converted, p is always a *T.
+type stdType struct {
+	parse  func(s string) (p any, err error)
+	format func(p any) string
+	clone  func(p any) any // nil for copying the value simply
+}
+
+//nolint:gochecknoglobals //i know that
+var (
+	stdTypes = map[reflect.Type]*stdType{
+		reflect.TypeOf(net.IP{}): {
+			parse: func(s string) (any, error) {
+				if ip := net.ParseIP(strings.TrimSpace(s)); ip != nil {
+					return &ip, nil
+				}
+				return nil, errors.New("invalid IP address")
+			},
+			format: func(p any) string { return p.(*net.IP).String() },
+			clone: func(p any) any {
+				ip := append(net.IP(nil), *p.(*net.IP)...)
+				return &ip
+			},
+		},
+		reflect.TypeOf(netip.Addr{}): {
+			parse: func(s string) (any, error) {
+				a, err := netip.ParseAddr(strings.TrimSpace(s))
+				return &a, err
+			},
+			format: func(p any) string { return p.(*netip.Addr).String() },
+		},
+		reflect.TypeOf(netip.Prefix{}): {
+			parse: func(s string) (any, error) {
+				a, err := netip.ParsePrefix(strings.TrimSpace(s))
+				return &a, err
+			},
+			format: func(p any) string { return p.(*netip.Prefix).String() },
+		},
+		reflect.TypeOf(netip.AddrPort{}): {
+			parse: func(s string) (any, error) {
+				a, err := netip.ParseAddrPort(strings.TrimSpace(s))
+				return &a, err
+			},
+			format: func(p any) string { return p.(*netip.AddrPort).String() },
+		},
+		reflect.TypeOf(url.URL{}): {
+			parse:  func(s string) (any, error) { return url.Parse(strings.TrimSpace(s)) },
+			format: func(p any) string { return p.(*url.URL).String() },
+		},
+		reflect.TypeOf(big.Int{}): {
+			parse: func(s string) (any, error) {
+				if x, ok := new(big.Int).SetString(strings.TrimSpace(s), 0); ok {
+					return x, nil
+				}
+				return nil, errors.New("invalid big.Int")
+			},
+			format: func(p any) string { return p.(*big.Int).String() },
+			clone:  func(p any) any { return new(big.Int).Set(p.(*big.Int)) },
+		},
+		reflect.TypeOf(big.Float{}): {
+			parse: func(s string) (any, error) {
+				if x, ok := new(big.Float).SetString(strings.TrimSpace(s)); ok {
+					return x, nil
+				}
+				return nil, errors.New("invalid big.Float")
+			},
+			format: func(p any) string { return p.(*big.Float).Text('g', -1) },
+			clone:  func(p any) any { return new(big.Float).Copy(p.(*big.Float)) },
+		},
+		reflect.TypeOf(big.Rat{}): {
+			parse: func(s string) (any, error) {
+				if x, ok := new(big.Rat).SetString(strings.TrimSpace(s)); ok {
+					return x, nil
+				}
+				return nil, errors.New("invalid big.Rat")
+			},
+			format: func(p any) string { return p.(*big.Rat).RatString() },
+			clone:  func(p any) any { return new(big.Rat).Set(p.(*big.Rat)) },
+		},
+		reflect.TypeOf(regexp.Regexp{}): {
+			parse:  func(s string) (any, error) { return regexp.Compile(s) },
+			format: func(p any) string { return p.(*regexp.Regexp).String() },
+			clone:  func(p any) any { return p }, // a Regexp is safe for concurrent use
+		},
+		reflect.TypeOf(fs.FileMode(0)): {
+			parse:  parseFileMode,
+			format: func(p any) string { return formatFileMode(*p.(*fs.FileMode)) },
+		},
+		reflect.TypeOf(time.Month(0)): {
+			parse: func(s string) (any, error) {
+				m, err := parseCalendarName(s, 1, 12, func(i int) string { return time.Month(i).String() }) //nolint:gomnd //months
+				month := time.Month(m)
+				return &month, err
+			},
+			format: func(p any) string { return p.(*time.Month).String() },
+		},
+		reflect.TypeOf(time.Weekday(0)): {
+			parse: func(s string) (any, error) {
+				d, err := parseCalendarName(s, 0, 6, func(i int) string { return time.Weekday(i).String() }) //nolint:gomnd //weekdays
+				day := time.Weekday(d)
+				return &day, err
+			},
+			format: func(p any) string { return p.(*time.Weekday).String() },
+		},
+	}
+
+	uuidType    = reflect.TypeOf([16]byte{})
+	uuidStdType = &stdType{
+		parse: parseUUID,
+		format: func(p any) string { // p might be a named type of *[16]byte
+			return formatUUID(reflect.ValueOf(p).Elem().Convert(uuidType).Interface().([16]byte))
+		},
+	}
+)
+
+// lookupStdType returns the stdType of t, a [16]byte (or a named type
+// of it) is treated as UUID.
+func lookupStdType(t reflect.Type) *stdType {
+	if st, ok := stdTypes[t]; ok {
+		return st
+	}
+	if t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 { //nolint:gomnd //uuid
+		return uuidStdType
+	}
+	return nil
+}
+
+// isKeptStructType tests if a struct field of typ should be copied as
+// a whole rather than being expanded, such as a user collection, a
+// type which can be unmarshalled, a lock, an immutable type, or a
+// url.URL if WithStdConverters is enabled.
+func (c *cpController) isKeptStructType(typ reflect.Type) bool {
+	return c.isCollectionType(typ) || isUnmarshalerType(typ) || isLockType(typ) ||
+		isImmutableType(typ) || c.stdConverters && lookupStdType(typ) != nil
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+type stdTypesConverter struct{ cvtbase }
+
+func (c *stdTypesConverter) CopyTo(ctx *ValueConverterContext, source, target reflect.Value) (err error) {
+	dbglog.Log("  target: %v (%v)", ref.Typfmtv(&target), ref.Typfmt(target.Type()))
+
+	var ret reflect.Value
+	switch {
+	case target.CanSet():
+		if ret, err = c.Transform(ctx, source, target.Type()); err == nil {
+			target.Set(ret)
+		}
+	case target.Kind() == reflect.Ptr && !target.IsNil():
+		if ret, err = c.Transform(ctx, source, target.Type().Elem()); err == nil {
+			target.Elem().Set(ret)
+		}
+	default:
+		err = ErrCannotSet.FormatWith(ref.Valfmt(&target), ref.Typfmtv(&target), ref.Valfmt(&source), ref.Typfmtv(&source))
+	}
+	return
+}
+
+//nolint:lll //keep it
+func (c *stdTypesConverter) Transform(ctx *ValueConverterContext, source reflect.Value, targetType reflect.Type) (target reflect.Value, err error) {
+	var sp reflect.Value // a *T of source
+	if source.Kind() == reflect.Ptr {
+		if source.IsNil() {
+			return reflect.Zero(targetType), nil
+		}
+		sp, source = source, source.Elem()
+	}
+	srcType, tgtType := source.Type(), derefType(targetType)
+
+	var pv reflect.Value // a *T of target
+	if st := lookupStdType(srcType); st != nil {
+		if !sp.IsValid() {
+			sp = reflect.New(srcType)
+			sp.Elem().Set(source)
+		}
+		switch {
+		case srcType == tgtType && st.clone != nil:
+			pv = reflect.ValueOf(st.clone(sp.Interface()))
+		case srcType == tgtType:
+			pv = reflect.New(tgtType)
+			pv.Elem().Set(source)
+		case tgtType.Kind() == reflect.String:
+			pv = reflect.New(tgtType)
+			pv.Elem().SetString(st.format(sp.Interface()))
+		default:
+			err = ErrCannotConvertTo.FormatWith(source, ref.Typfmtv(&source), targetType, targetType.Kind())
+			return
+		}
+	} else if st = lookupStdType(tgtType); st != nil && source.Kind() == reflect.String {
+		str := source.String()
+		var p any
+		if p, err = st.parse(str); err != nil {
+			err = &ConversionError{Value: str, Target: tgtType, Err: fmt.Errorf("%w: %v", ErrBadSyntax, err)}
+			return
+		}
+		if pv = reflect.ValueOf(p); pv.Type().Elem() != tgtType { // a named type of [16]byte
+			np := reflect.New(tgtType)
+			np.Elem().Set(pv.Elem().Convert(tgtType))
+			pv = np
+		}
+	} else {
+		err = ErrCannotConvertTo.FormatWith(source, ref.Typfmtv(&source), targetType, targetType.Kind())
+		return
+	}
+
+	if targetType.Kind() == reflect.Ptr {
+		target = pv
+	} else {
+		target = pv.Elem()
+	}
+	return
+}
+
+func (c *stdTypesConverter) Match(params *Params, source, target reflect.Type) (ctx *ValueConverterContext, yes bool) { //nolint:revive,lll
+	st, tt := derefType(source), derefType(target)
+	if lookupStdType(st) != nil {
+		yes = tt == st || tt.Kind() == reflect.String
+	} else if lookupStdType(tt) != nil {
+		yes = st.Kind() == reflect.String
+	}
+	if yes {
+		ctx = &ValueConverterContext{params}
+	}
+	return
+}
+
+// parseFileMode parses an octal "0644", or a symbolic "drwxr-xr-x"
+// which is formatted by fs.FileMode.String.
+func parseFileMode(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.ParseUint(s, 8, 32); err == nil {
+		m := fs.FileMode(v)
+		return &m, nil
+	}
+
+	const typeChars = "dalTLDpSugct?" // same as fs.FileMode.String
+	const permChars = "rwxrwxrwx"
+	if len(s) < len(permChars) {
+		return nil, errors.New("invalid file mode")
+	}
+	var m fs.FileMode
+	for _, c := range s[:len(s)-len(permChars)] {
+		i := strings.IndexRune(typeChars, c)
+		if i < 0 {
+			if c == '-' {
+				continue
+			}
+			return nil, errors.New("invalid file mode")
+		}
+		m |= 1 << uint(32-1-i) //nolint:gomnd //bits of fs.ModeDir...
+	}
+	for i, c := range s[len(s)-len(permChars):] {
+		switch c {
+		case rune(permChars[i]):
+			m |= 1 << uint(len(permChars)-1-i)
+		case '-':
+		default:
+			return nil, errors.New("invalid file mode")
+		}
+	}
+	return &m, nil
+}
+
+// formatFileMode formats the permission bits in octal, such as "0644",
+// or in symbolic by fs.FileMode.String if it has any type bits.
+func formatFileMode(m fs.FileMode) string {
+	if m&^fs.ModePerm == 0 {
+		return fmt.Sprintf("%#o", uint32(m))
+	}
+	return m.String()
+}
+
+// parseCalendarName parses a number in [minV, maxV], or a name (or
+// its three-letter abbreviation) case-insensitively.
+func parseCalendarName(s string, minV, maxV int, name func(i int) string) (int, error) {
+	s = strings.TrimSpace(s)
+	if v, err := strconv.Atoi(s); err == nil {
+		if v < minV || v > maxV {
+			return 0, errors.New("out of range")
+		}
+		return v, nil
+	}
+	for i := minV; i <= maxV; i++ {
+		if n := name(i); strings.EqualFold(s, n) || len(s) == 3 && strings.EqualFold(s, n[:3]) { //nolint:gomnd //abbr
+			return i, nil
+		}
+	}
+	return 0, errors.New("unknown name")
+}
+
+// parseUUID parses "123e4567-e89b-12d3-a456-426614174000", with or
+// without the hyphens, braces, or the "urn:uuid:" prefix.
+func parseUUID(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "urn:uuid:"), "{")
+	s = strings.ReplaceAll(strings.TrimSuffix(s, "}"), "-", "")
+
+	var u [16]byte
+	if len(s) != hex.EncodedLen(len(u)) {
+		return nil, errors.New("invalid UUID length")
+	}
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func formatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
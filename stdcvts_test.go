@@ -0,0 +1,142 @@
+package evendeep_test
+
+import (
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+)
+
+type uuid [16]byte
+
+type stdConfig struct {
+	IP      net.IP
+	Addr    netip.Addr
+	Prefix  netip.Prefix
+	Listen  netip.AddrPort
+	URL     *url.URL
+	Home    url.URL
+	Big     *big.Int
+	Float   *big.Float
+	Rat     big.Rat
+	Pattern *regexp.Regexp
+	Mode    os.FileMode
+	Dir     os.FileMode
+	Month   time.Month
+	Weekday time.Weekday
+	ID      uuid
+}
+
+type stdConfigStrings struct {
+	IP      string
+	Addr    string
+	Prefix  string
+	Listen  string
+	URL     string
+	Home    string
+	Big     string
+	Float   string
+	Rat     string
+	Pattern string
+	Mode    string
+	Dir     string
+	Month   string
+	Weekday string
+	ID      string
+}
+
+func TestWithStdConverters(t *testing.T) {
+	src := stdConfigStrings{
+		IP:      "10.0.0.1",
+		Addr:    "::1",
+		Prefix:  "10.0.0.0/8",
+		Listen:  "127.0.0.1:80",
+		URL:     "https://example.com/a?b=c",
+		Home:    "file:///home/me",
+		Big:     "0x1f",
+		Float:   "1.5e100",
+		Rat:     "3/4",
+		Pattern: "^a+$",
+		Mode:    "0644",
+		Dir:     "drwxr-xr-x",
+		Month:   "mar",
+		Weekday: "Friday",
+		ID:      "{123E4567-E89B-12D3-A456-426614174000}",
+	}
+
+	var cfg stdConfig
+	if err := evendeep.New(evendeep.WithStdConverters()).CopyTo(src, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	switch {
+	case !cfg.IP.Equal(net.IPv4(10, 0, 0, 1)),
+		cfg.Addr != netip.IPv6Loopback(),
+		cfg.Prefix != netip.MustParsePrefix("10.0.0.0/8"),
+		cfg.URL == nil || cfg.URL.Host != "example.com" || cfg.URL.RawQuery != "b=c",
+		cfg.Home.Path != "/home/me",
+		cfg.Big == nil || cfg.Big.Int64() != 31,
+		cfg.Float == nil || cfg.Float.Text('g', -1) != "1.5e+100",
+		cfg.Rat.RatString() != "3/4",
+		cfg.Pattern == nil || !cfg.Pattern.MatchString("aaa"),
+		cfg.Mode != 0o644,
+		cfg.Dir != os.ModeDir|0o755,
+		cfg.Month != time.March,
+		cfg.Weekday != time.Friday,
+		cfg.ID != uuid{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}:
+		t.Fatalf("bad result: %+v", cfg)
+	}
+
+	// and back to strings
+	var out stdConfigStrings
+	if err := evendeep.New(evendeep.WithStdConverters()).CopyTo(cfg, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := src
+	want.Big, want.Float, want.Month, want.ID = "31", "1.5e+100", "March", "123e4567-e89b-12d3-a456-426614174000"
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("bad result:\n got %+v\nwant %+v", out, want)
+	}
+
+	// and clone
+	var clone stdConfig
+	if err := evendeep.New(evendeep.WithStdConverters()).CopyTo(&cfg, &clone); err != nil {
+		t.Fatal(err)
+	}
+	if clone.Big == cfg.Big || clone.Big.Cmp(cfg.Big) != 0 || clone.URL == cfg.URL || *clone.URL != *cfg.URL {
+		t.Fatalf("bad clone: %+v", clone)
+	}
+	if clone.Pattern != cfg.Pattern {
+		t.Fatal("a compiled regexp should be shared")
+	}
+	cfg.Big.SetInt64(7)
+	cfg.IP[3] = 9
+	if clone.Big.Int64() != 31 || clone.IP.String() != "10.0.0.1" {
+		t.Fatalf("clone shares the words with source: %v, %v", clone.Big, clone.IP)
+	}
+}
+
+func TestWithStdConverters_BadSyntax(t *testing.T) {
+	for _, src := range []map[string]any{
+		{"IP": "10.0.0.256"},
+		{"Pattern": "a("},
+		{"Month": "13"},
+		{"ID": "not-a-uuid"},
+	} {
+		var cfg stdConfig
+		err := evendeep.New(evendeep.WithStdConverters()).CopyTo(src, &cfg)
+		var ce *evendeep.ConversionError
+		if !errors.Is(err, evendeep.ErrBadSyntax) || !errors.As(err, &ce) {
+			t.Fatalf("want ErrBadSyntax for %v, got %v", src, err)
+		}
+		t.Log(err)
+	}
+}
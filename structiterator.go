@@ -19,8 +19,11 @@ import (
 type fieldsTableT struct {
 	tableRecordsT
 	autoExpandStruct bool
-	typ              reflect.Type  // struct type
-	val              reflect.Value // struct value
+	keepStructPtr    bool                        // don't expand the pointer to struct
+	keepStruct       func(typ reflect.Type) bool // don't expand the struct if it returns true
+	expanding        []reflect.Type              // the struct types being expanded, to stop at a recursive type
+	typ              reflect.Type                // struct type
+	val              reflect.Value               // struct value
 	fastIndices      map[string]*tableRecT
 }
 
@@ -76,6 +79,7 @@ func (table *fieldsTableT) getAllFields(structValue reflect.Value, autoexpandstr
 	}
 
 	styp := structValue.Type()
+	table.expanding = append(table.expanding[:0], styp)
 	ret := table.getFields(&structValue, styp, "", -1)
 	table.tableRecordsT = append(table.tableRecordsT, ret...)
 
@@ -127,7 +131,8 @@ func (table *fieldsTableT) getFields(structValue *reflect.Value, structType refl
 
 		tr = table.tableRec(svind, &sf, i, fi, fieldName)
 
-		if isStruct && table.autoExpandStruct && !isReservedPackage {
+		if isStruct && table.autoExpandStruct && !isReservedPackage &&
+			!table.shouldKeepStructPtr(sftyp, sftypind) {
 			if internal.VerboseStructIterating {
 				// only printed on `-tags="structiterating,verbose"
 				dbglog.Log(" field %d: %v (%v) (%v) || %v", i, sf.Name,
@@ -136,7 +141,9 @@ func (table *fieldsTableT) getFields(structValue *reflect.Value, structType refl
 
 			if !tr.ShouldIgnore() {
 				// struct, or pointer to struct has been found and we will get into it
+				table.expanding = append(table.expanding, sftypind)
 				n := table.getFields(svind, sftypind, sf.Name, i)
+				table.expanding = table.expanding[:len(table.expanding)-1]
 				if len(n) > 0 {
 					ret = append(ret, n...)
 				} else {
@@ -156,6 +163,29 @@ func (table *fieldsTableT) getFields(structValue *reflect.Value, structType refl
 	return
 }
 
+// shouldKeepStructPtr tests if a struct field should be kept as is
+// rather than being expanded.
+//
+// A recursive type, such as a back-pointer to parent, is never
+// expanded, or the expanding will be endless. The field will be
+// copied by copyPointer which can close the cycle.
+//
+// A collection type (see withStructKept) is never expanded too.
+func (table *fieldsTableT) shouldKeepStructPtr(typ, typind reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr && table.keepStructPtr {
+		return true
+	}
+	if table.keepStruct != nil && table.keepStruct(typind) {
+		return true
+	}
+	for _, t := range table.expanding {
+		if t == typind {
+			return true
+		}
+	}
+	return false
+}
+
 //nolint:lll //keep it
 func (table *fieldsTableT) tableRec(svind *reflect.Value, sf *reflect.StructField, index, parentIndex int, parentFieldName string) (tr *tableRecT) {
 	tr = new(tableRecT)
@@ -234,10 +264,33 @@ func withStructFieldPtrAutoNew(create bool) structIterableOpt {
 	}
 }
 
+// withStructPtrKept keeps the pointer to struct as a field rather than
+// expanding it, so that it can be copied by copyPointer.
+//
+// It must be applied before withStructSource.
+func withStructPtrKept(keep bool) structIterableOpt {
+	return func(s *structIteratorT) {
+		s.keepStructPtr = keep
+	}
+}
+
+// withStructKept keeps the struct fields as is rather than expanding
+// them if keep returns true, such as the user collections which should
+// be copied by copyCollection.
+//
+// It must be applied before withStructSource.
+func withStructKept(keep func(typ reflect.Type) bool) structIterableOpt {
+	return func(s *structIteratorT) {
+		s.keepStruct = keep
+	}
+}
+
 // withStructSource _.
 func withStructSource(srcstructval *reflect.Value, autoexpand bool) structIterableOpt {
 	return func(s *structIteratorT) {
 		if srcstructval != nil {
+			s.srcFields.keepStructPtr = s.keepStructPtr
+			s.srcFields.keepStruct = s.keepStruct
 			s.srcFields = s.srcFields.getAllFields(*srcstructval, autoexpand)
 			s.withSourceIteratorIndexIncrease(-10000) // reset srcIndex to 0
 		}
@@ -247,14 +300,16 @@ func withStructSource(srcstructval *reflect.Value, autoexpand bool) structIterab
 //
 
 type structIteratorT struct {
-	srcFields                fieldsTableT      // source struct fields accessors
-	srcIndex                 int               // source field index
-	dstStruct                reflect.Value     // target struct
-	dstIndex                 int               // counter for Next()
-	stack                    []*fieldAccessorT // target fields accessors
-	autoExpandStruct         bool              // Next() will expand *struct to struct and get inside loop deeply
-	noExpandIfSrcFieldIsFunc bool              //
-	autoNew                  bool              // create new inner objects for the child ptr,map,chan,..., if necessary
+	srcFields                fieldsTableT                // source struct fields accessors
+	srcIndex                 int                         // source field index
+	dstStruct                reflect.Value               // target struct
+	dstIndex                 int                         // counter for Next()
+	stack                    []*fieldAccessorT           // target fields accessors
+	autoExpandStruct         bool                        // Next() will expand *struct to struct and get inside loop deeply
+	keepStructPtr            bool                        // but don't expand *struct
+	keepStruct               func(typ reflect.Type) bool // and don't expand the struct if it returns true
+	noExpandIfSrcFieldIsFunc bool                        //
+	autoNew                  bool                        // create new inner objects for the child ptr,map,chan,..., if necessary
 }
 
 // accessor represents a struct field accessor which can be used for getting or setting.
@@ -880,6 +935,10 @@ retryExpand:
 			tind := ref.RindirectType(field.Type)
 			k1 := tind.Kind()
 			dbglog.Log("   typ: %v, name: %v | %v", ref.Typfmt(tind), field.Name, field)
+			if k1 == reflect.Struct && s.shouldKeepStructPtr(field.Type, tind) {
+				ok, accessor = true, lastone // keep it, don't new or expand it
+				return
+			}
 			if s.autoNew {
 				did := lastone.ensurePtrField()
 				if did { //nolint:revive
@@ -962,6 +1021,23 @@ func (s *structIteratorT) getTargetFieldNameBySourceField(knownSrcField *reflect
 	return
 }
 
+// shouldKeepStructPtr is the target side of
+// fieldsTableT.shouldKeepStructPtr.
+func (s *structIteratorT) shouldKeepStructPtr(typ, typind reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr && s.keepStructPtr {
+		return true
+	}
+	if s.keepStruct != nil && s.keepStruct(typind) {
+		return true
+	}
+	for _, acc := range s.stack {
+		if acc.structType == typind {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *structIteratorT) typShouldBeIgnored(typ reflect.Type) bool {
 	n := typ.PkgPath()
 	return packageisreserved(n) // ignore golang stdlib, such as "io", "runtime", ...
@@ -3,6 +3,8 @@ package evendeep
 import (
 	"reflect"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/hedzr/evendeep/flags"
 	"github.com/hedzr/evendeep/flags/cms"
@@ -10,12 +12,39 @@ import (
 
 // parseFieldTags gets the struct field tag string by 'tagKeyName', and
 // parse the string into a fieldTags object and return it.
+//
+// The result is cached per tag and shared, the caller must not modify
+// it.
 func parseFieldTags(tag reflect.StructTag, tagName string) *fieldTags {
+	key := fieldTagsKey{tag, tagName}
+	if t, ok := fieldTagsCache.Load(key); ok {
+		return t.(*fieldTags) //nolint:forcetypeassert //it's always *fieldTags
+	}
 	t := &fieldTags{}
 	t.Parse(tag, tagName)
+	if fieldTagsCached.Add(1) <= maxCachedFieldTags {
+		fieldTagsCache.Store(key, t)
+	}
 	return t
 }
 
+type fieldTagsKey struct {
+	tag     reflect.StructTag
+	tagName string
+}
+
+// maxCachedFieldTags bounds fieldTagsCache. The tags of a program are
+// a few hundreds or thousands, and they're parsed for every copy of
+// their structs, so they're cached; the tags beyond the bound (such as
+// the ones of the struct types made by reflect.StructOf at runtime)
+// are parsed each time rather than growing the cache without limit.
+const maxCachedFieldTags = 4096
+
+var (
+	fieldTagsCache  sync.Map     //nolint:gochecknoglobals //i know that
+	fieldTagsCached atomic.Int32 //nolint:gochecknoglobals //i know that
+)
+
 // fieldTags collect the flags and others which are parsed from a struct field tags definition.
 //
 //	type sample struct {
@@ -35,6 +64,61 @@ type fieldTags struct {
 	// "->dstName"         from source field to 'dstName' field (thinking about name converters too)
 	// "srcName->dstName"  from 'srcName' to 'dstName' field
 	nameConvertRule flags.NameConvertRule //nolint:revive,unused // first section in struct field tag, such as: "someName,must,..."
+
+	// discriminator is the key in source map to resolve the concrete
+	// type of interface target, such as: ",discriminator=kind"
+	discriminator string
+
+	// numberFormat is the name of NumberFormat to parse a string to
+	// number, such as: ",parse=bytesize"
+	numberFormat string
+
+	// timeFormat is the layout to parse a string to time, or format a
+	// time to string, such as: ",timefmt=2006-01-02"
+	timeFormat string
+
+	// epochUnit is the unit of an integer epoch converted from/to time,
+	// one of s, ms, us and ns, such as: ",epoch=ms"
+	epochUnit string
+
+	// durationFormat is the format of a time.Duration converted to
+	// string, such as: ",durfmt=iso8601"
+	durationFormat string
+
+	// chanStrategy is the way to copy a channel, one of share, fresh
+	// and snapshot, such as: ",chan=snapshot"
+	chanStrategy string
+
+	// keyTransform is the name of MapKeyTransform to rename the keys
+	// in copying a map, such as: ",keys=lower"
+	keyTransform string
+
+	// keyCollision is the policy for the colliding map keys, one of
+	// error, first and last, such as: ",collide=last"
+	keyCollision string
+
+	// mapMergePolicy is the policy to merge a key which exists in both
+	// maps, one of deep, override, keep and a registered resolver,
+	// such as: ",mapmerge=override"
+	mapMergePolicy string
+
+	// dedupKey is the name of the field (or the map key) of slice
+	// elements to dedup them with cms.SliceDedup, such as:
+	// ",slicededup,dedupkey=ID"
+	dedupKey string
+
+	// lengthPolicy is the policy to copy between arrays and slices of
+	// different lengths, one of truncate, pad, strict and resize, such
+	// as: ",len=strict"
+	lengthPolicy string
+
+	// funcAdapter wraps a function of the different type into an
+	// adapter, such as: ",adapt"
+	funcAdapter bool
+
+	// share shares the source value with the target rather than
+	// copying it, such as: ",share"
+	share bool
 }
 
 type nameConverterFunc func(source string, ctx *NameConverterContext) (target string, ok bool)
@@ -62,6 +146,43 @@ func (f *fieldTags) isFlagShallow() bool { return f.isFlagExists(cms.Shallow) }
 
 func (f *fieldTags) Parse(s reflect.StructTag, tagName string) {
 	f.flags, f.nameConvertRule = flags.Parse(s, tagName)
+
+	if tagName == "" {
+		tagName = flags.CopyTagName
+	}
+	for i, word := range strings.Split(s.Get(tagName), ",") {
+		if i == 0 {
+			continue // the name convert rule
+		}
+		switch k, v, _ := strings.Cut(word, "="); k {
+		case "discriminator":
+			f.discriminator = v
+		case "parse":
+			f.numberFormat = v
+		case "timefmt":
+			f.timeFormat = v
+		case "epoch":
+			f.epochUnit = v
+		case "durfmt":
+			f.durationFormat = v
+		case "chan":
+			f.chanStrategy = v
+		case "keys":
+			f.keyTransform = v
+		case "collide":
+			f.keyCollision = v
+		case "mapmerge":
+			f.mapMergePolicy = v
+		case "dedupkey":
+			f.dedupKey = v
+		case "len":
+			f.lengthPolicy = v
+		case "adapt":
+			f.funcAdapter = true
+		case "share":
+			f.share = true
+		}
+	}
 }
 
 func (f *fieldTags) CalcSourceName(dstName string) (srcName string, ok bool) {
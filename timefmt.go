@@ -0,0 +1,202 @@
+package evendeep
+
+import (
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/hedzr/evendeep/times"
+)
+
+//nolint:gochecknoglobals //i know that
+var epochUnits = map[string]time.Duration{
+	"s":  time.Second,
+	"ms": time.Millisecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ns": time.Nanosecond,
+}
+
+// timeLayouts returns the layouts to parse a string to time, the
+// `timefmt=` tag of the nearest target field wins, and then the
+// layouts given by WithTimeLayouts. It returns nil for the layouts
+// registered in package times.
+func (params *Params) timeLayouts() []string {
+	if params == nil {
+		return nil
+	}
+	if tags := params.nearestFieldTags(); tags != nil && tags.timeFormat != "" {
+		return []string{tags.timeFormat}
+	}
+	if params.controller != nil {
+		return params.controller.timeLayouts
+	}
+	return nil
+}
+
+// timeFormat returns the layout to format a time to string, the
+// `timefmt=` tag of the nearest target field wins, and then the
+// layout given by WithTimeFormat, or time.RFC3339.
+func (params *Params) timeFormat() string {
+	if params != nil {
+		if tags := params.nearestFieldTags(); tags != nil && tags.timeFormat != "" {
+			return tags.timeFormat
+		}
+		if params.controller != nil && params.controller.timeFormat != "" {
+			return params.controller.timeFormat
+		}
+	}
+	return time.RFC3339
+}
+
+// timeLocation returns the location given by WithTimeLocation, or
+// nil if it's not specified.
+func (params *Params) timeLocation() (loc *time.Location) {
+	if params != nil && params.controller != nil {
+		loc = params.controller.timeLocation
+	}
+	return
+}
+
+// epochUnit returns the unit of an integer epoch, the `epoch=` tag of
+// the nearest target field wins, and then the unit given by
+// WithEpochUnit, or time.Second. It reports ErrUnknownName for an
+// unknown unit in the tag.
+func (params *Params) epochUnit() (unit time.Duration, err error) {
+	if params != nil {
+		if tags := params.nearestFieldTags(); tags != nil && tags.epochUnit != "" {
+			var ok bool
+			if unit, ok = epochUnits[tags.epochUnit]; !ok {
+				err = ErrUnknownName.FormatWith("epoch unit", tags.epochUnit)
+			}
+			return
+		}
+		if params.controller != nil && params.controller.epochUnit > 0 {
+			return params.controller.epochUnit, nil
+		}
+	}
+	return time.Second, nil
+}
+
+// parseTime parses str with the layouts in order. The layouts without
+// zone are parsed in the location given by WithTimeLocation, or UTC.
+func (params *Params) parseTime(str string) (tm time.Time, err error) {
+	loc := params.timeLocation()
+	if loc == nil {
+		loc = time.UTC
+	}
+	layouts := params.timeLayouts()
+	if len(layouts) == 0 {
+		return times.SmartParseTimeInLocation(str, loc)
+	}
+	for _, layout := range layouts {
+		if tm, err = time.ParseInLocation(layout, str, loc); err == nil {
+			return
+		}
+	}
+	return
+}
+
+// formatTime formats tm in the location given by WithTimeLocation.
+func (params *Params) formatTime(tm time.Time) string {
+	if loc := params.timeLocation(); loc != nil {
+		tm = tm.In(loc)
+	}
+	return tm.Format(params.timeFormat())
+}
+
+// fromEpoch makes a time from an epoch number in unit.
+func (params *Params) fromEpoch(n int64) (tm time.Time, err error) {
+	unit, err := params.epochUnit()
+	if err != nil {
+		return
+	}
+	switch unit {
+	case time.Second:
+		tm = time.Unix(n, 0)
+	case time.Millisecond:
+		tm = time.UnixMilli(n)
+	case time.Microsecond:
+		tm = time.UnixMicro(n)
+	default:
+		tm = time.Unix(0, n*int64(unit))
+	}
+	if loc := params.timeLocation(); loc != nil {
+		tm = tm.In(loc)
+	}
+	return
+}
+
+// fromEpochFloat makes a time from an epoch number in unit, the
+// fraction is kept to nanoseconds.
+func (params *Params) fromEpochFloat(f float64) (tm time.Time, err error) {
+	unit, err := params.epochUnit()
+	if err != nil {
+		return
+	}
+	sec, dec := math.Modf(f * float64(unit) / float64(time.Second))
+	tm = time.Unix(int64(sec), int64(dec*float64(time.Second)))
+	if loc := params.timeLocation(); loc != nil {
+		tm = tm.In(loc)
+	}
+	return
+}
+
+// toEpoch returns the epoch number of tm in unit.
+func (params *Params) toEpoch(tm time.Time) (n int64, err error) {
+	unit, err := params.epochUnit()
+	switch {
+	case err != nil:
+	case unit == time.Second:
+		n = tm.Unix()
+	case unit == time.Millisecond:
+		n = tm.UnixMilli()
+	case unit == time.Microsecond:
+		n = tm.UnixMicro()
+	default:
+		n = tm.UnixNano() / int64(unit)
+	}
+	return
+}
+
+// toEpochFloat returns the epoch number of tm in unit, with fraction.
+func (params *Params) toEpochFloat(tm time.Time) (f float64, err error) {
+	unit, err := params.epochUnit()
+	if err == nil {
+		f = float64(tm.UnixNano()) / float64(unit)
+	}
+	return
+}
+
+// Duration format names for WithDurationFormat and the `durfmt=` tag.
+const (
+	DurationFormatGo      = "go"      // "26h0m0s", by time.Duration.String
+	DurationFormatISO8601 = "iso8601" // "P1DT2H", by times.FormatISODuration
+	DurationFormatSmart   = "smart"   // "1d2h", by times.SmartDurationString
+)
+
+// formatDuration formats a time.Duration by the `durfmt=` tag of the
+// nearest target field, or by WithDurationFormat. It returns ok =
+// false for the Go style, and ErrUnknownName for an unknown format.
+func (ctx *ValueConverterContext) formatDuration(source reflect.Value) (str string, ok bool, err error) {
+	if ctx == nil || ctx.Params == nil {
+		return
+	}
+	name := ""
+	if tags := ctx.nearestFieldTags(); tags != nil && tags.durationFormat != "" {
+		name = tags.durationFormat
+	} else if ctx.controller != nil {
+		name = ctx.controller.durationFormat
+	}
+	d := time.Duration(source.Int())
+	switch name {
+	case DurationFormatISO8601:
+		str, ok = times.FormatISODuration(d), true
+	case DurationFormatSmart:
+		str, ok = times.SmartDurationString(d), true
+	case DurationFormatGo, "":
+	default:
+		err = ErrUnknownName.FormatWith("duration format", name)
+	}
+	return
+}
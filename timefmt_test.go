@@ -0,0 +1,190 @@
+package evendeep_test
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/hedzr/errors.v3"
+
+	"github.com/hedzr/evendeep"
+	"github.com/hedzr/evendeep/times"
+)
+
+func TestWithTimeLayouts(t *testing.T) {
+	type row struct {
+		Born    time.Time `copy:",timefmt=02.01.2006"`
+		Updated time.Time
+	}
+	src := map[string]any{"Born": "03.02.2001", "Updated": "2024/05/06 07:08"}
+
+	var tgt row
+	err := evendeep.New(evendeep.WithTimeLayouts("2006/01/02 15:04")).CopyTo(src, &tgt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC); !tgt.Born.Equal(want) {
+		t.Fatalf("want Born is %v, got %v", want, tgt.Born)
+	}
+	if want := time.Date(2024, 5, 6, 7, 8, 0, 0, time.UTC); !tgt.Updated.Equal(want) {
+		t.Fatalf("want Updated is %v, got %v", want, tgt.Updated)
+	}
+
+	// time to string
+	type out struct {
+		Born    string `copy:",timefmt=2006-01-02"`
+		Updated string
+	}
+	var o out
+	if err = evendeep.New(evendeep.WithTimeFormat(time.DateTime)).CopyTo(tgt, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.Born != "2001-02-03" || o.Updated != "2024-05-06 07:08:00" {
+		t.Fatalf("bad result: %+v", o)
+	}
+}
+
+func TestWithTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+8", 8*3600)
+	var tgt struct{ At time.Time }
+	err := evendeep.New(evendeep.WithTimeLocation(loc)).CopyTo(map[string]any{"At": "2024-05-06 07:08:09"}, &tgt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 5, 5, 23, 8, 9, 0, time.UTC); !tgt.At.Equal(want) {
+		t.Fatalf("want %v, got %v", want, tgt.At)
+	}
+
+	var o struct{ At string }
+	at := time.Date(2024, 5, 5, 23, 8, 9, 0, time.UTC)
+	if err = evendeep.New(evendeep.WithTimeLocation(loc)).CopyTo(struct{ At time.Time }{at}, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.At != "2024-05-06T07:08:09+08:00" {
+		t.Fatalf("bad result: %+v", o)
+	}
+}
+
+func TestWithEpochUnit(t *testing.T) {
+	at := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+
+	var tgt struct {
+		Sec   time.Time
+		Milli time.Time `copy:",epoch=ms"`
+	}
+	src := map[string]any{"Sec": at.Unix(), "Milli": at.UnixMilli()}
+	if err := evendeep.New().CopyTo(src, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if !tgt.Sec.Equal(at.Truncate(time.Second)) || !tgt.Milli.Equal(at) {
+		t.Fatalf("bad result: %+v", tgt)
+	}
+
+	var o struct{ At int64 }
+	if err := evendeep.New(evendeep.WithEpochUnit(time.Microsecond)).CopyTo(struct{ At time.Time }{at}, &o); err != nil {
+		t.Fatal(err)
+	}
+	if o.At != at.UnixMicro() {
+		t.Fatalf("want %v, got %v", at.UnixMicro(), o.At)
+	}
+
+	var tgt2 struct{ At time.Time }
+	if err := evendeep.New(evendeep.WithEpochUnit(time.Nanosecond)).CopyTo(map[string]any{"At": at.UnixNano()}, &tgt2); err != nil {
+		t.Fatal(err)
+	}
+	if !tgt2.At.Equal(at) {
+		t.Fatalf("want %v, got %v", at, tgt2.At)
+	}
+}
+
+func TestWithEpochUnit_unknown(t *testing.T) {
+	var tgt struct {
+		At time.Time `copy:",epoch=msec"`
+	}
+	err := evendeep.New().CopyTo(map[string]any{"At": int64(1700000000000)}, &tgt)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v (%v)", err, tgt.At)
+	}
+
+	var o struct {
+		At int64 `copy:",epoch=msec"`
+	}
+	err = evendeep.New().CopyTo(struct{ At time.Time }{time.Now()}, &o)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v (%v)", err, o.At)
+	}
+}
+
+func TestAddKnownTimeFormats(t *testing.T) {
+	times.AddKnownTimeFormats("2006.01.02 15h04")
+	want := time.Date(2024, 5, 6, 7, 8, 0, 0, time.UTC)
+
+	var tgt struct{ At time.Time }
+	if err := evendeep.New().CopyTo(map[string]any{"At": "2024.05.06 07h08"}, &tgt); err != nil {
+		t.Fatal(err)
+	}
+	if !tgt.At.Equal(want) {
+		t.Fatalf("want %v, got %v", want, tgt.At)
+	}
+
+	var cvt evendeep.Cvt
+	if tm := cvt.Time("2024.05.06 07h08"); !tm.Equal(want) {
+		t.Fatalf("want %v, got %v", want, tm)
+	}
+	if tm, err := evendeep.As[time.Time]("2024.05.06 07h08"); err != nil || !tm.Equal(want) {
+		t.Fatalf("want %v, got %v, %v", want, tm, err)
+	}
+}
+
+func TestWithDurationFormat(t *testing.T) {
+	type config struct {
+		Timeout  time.Duration
+		Interval time.Duration
+		Retry    time.Duration
+	}
+	src := map[string]any{"Timeout": "P1DT2H", "Interval": "1 day 2 hours", "Retry": "1w"}
+
+	var cfg config
+	if err := evendeep.New().CopyTo(src, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	want := 26 * time.Hour
+	if cfg.Timeout != want || cfg.Interval != want || cfg.Retry != 7*24*time.Hour {
+		t.Fatalf("bad result: %+v", cfg)
+	}
+
+	var out struct {
+		Timeout  string
+		Interval string `copy:",durfmt=smart"`
+		Retry    string `copy:",durfmt=go"`
+	}
+	if err := evendeep.New(evendeep.WithDurationFormat(evendeep.DurationFormatISO8601)).CopyTo(cfg, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Timeout != "P1DT2H" || out.Interval != "1d2h" || out.Retry != "168h0m0s" {
+		t.Fatalf("bad result: %+v", out)
+	}
+
+	var back config
+	if err := evendeep.New().CopyTo(out, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back != cfg {
+		t.Fatalf("round-trip failed: %+v", back)
+	}
+}
+
+func TestWithDurationFormat_unknown(t *testing.T) {
+	var out struct {
+		Timeout string `copy:",durfmt=isoo"`
+	}
+	err := evendeep.New().CopyTo(struct{ Timeout time.Duration }{time.Hour}, &out)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v (%q)", err, out.Timeout)
+	}
+
+	var o struct{ Timeout string }
+	err = evendeep.New(evendeep.WithDurationFormat("iso")).CopyTo(struct{ Timeout time.Duration }{time.Hour}, &o)
+	if !errors.Is(err, evendeep.ErrUnknownName) {
+		t.Fatalf("want ErrUnknownName, got %v (%q)", err, o.Timeout)
+	}
+}
@@ -3,6 +3,7 @@ package times
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 )
 
@@ -267,8 +268,26 @@ func MustParseDuration(s string) (dur time.Duration) {
 // such as "300ms", "-1.5h" or "2h45m".
 // Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
 //
-// The difference is we accept day part, such as '3d7s'.
-func ParseDuration(s string) (time.Duration, error) { //nolint:revive
+// The difference is we accept day and week parts, such as '3d7s'
+// and '1w', the ISO 8601 durations such as "P1DT2H" and "PT15M" (see
+// ParseISODuration), and the human-written durations such as
+// "1 day 2 hours" and "3 weeks, 1 min".
+func ParseDuration(s string) (time.Duration, error) {
+	if t := strings.TrimLeft(s, "+-"); strings.HasPrefix(t, "P") {
+		return ParseISODuration(s)
+	}
+	d, err := parseGoDuration(s)
+	if err != nil {
+		if norm, ok := normalizeHumanDuration(s); ok && norm != s {
+			if d1, e := parseGoDuration(norm); e == nil {
+				return d1, nil
+			}
+		}
+	}
+	return d, err
+}
+
+func parseGoDuration(s string) (time.Duration, error) { //nolint:revive
 	// [-+]?([0-9]*(\.[0-9]*)?[a-z]+)+
 	orig := s
 	var d uint64
@@ -431,4 +450,5 @@ var unitMap = map[string]uint64{
 	"m":  uint64(time.Minute),
 	"h":  uint64(time.Hour),
 	"d":  uint64(24 * time.Hour),
+	"w":  uint64(7 * 24 * time.Hour),
 }
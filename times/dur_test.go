@@ -0,0 +1,107 @@
+package times
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSmartDurationString(t *testing.T) {
+	data := []time.Duration{
+		10*time.Hour + 11*time.Second + 13*time.Microsecond,
+		10*time.Hour + 11*time.Minute + 13*time.Millisecond + 15*time.Nanosecond,
+		15 * time.Nanosecond,
+		-15 * time.Nanosecond,
+		0,
+	}
+
+	t.Logf("%-32s %-32s %-32s %-32s\n", "d.String()", "shortDur(d)", "shortDur(d,true)", "Parsed(shortDur(d))")
+	t.Logf("%-32s %-32s %-32s %-32s\n", "----------", "-----------", "----------------", "-------------------")
+	for _, d := range data {
+		t.Logf("%-32v %-32v %-32v %-32v / %v\n", d,
+			SmartDurationString(d),
+			SmartDurationStringEx(d, true),
+			MustParseDuration(shortDur(d, false)),
+			shortDurSimple(d),
+		)
+	}
+
+	f := 11.013000015
+	d := DurationFromFloat(f)
+	t.Logf("%v", SmartDurationString(d))
+}
+
+func TestParseDuration(t *testing.T) {
+	data := []string{
+		"10h0m11.000013s",
+		"0s",
+		"0",
+		"",
+	}
+	for _, str := range data {
+		d := MustParseDuration(str)
+		t.Logf("%-32v %-32v %-32v %-32v / %v\n", d,
+			SmartDurationString(d),
+			SmartDurationStringEx(d, true),
+			MustParseDuration(shortDur(d, false)),
+			shortDurSimple(d),
+		)
+	}
+}
+
+func TestParseDurationExtended(t *testing.T) {
+	day := 24 * time.Hour
+	for i, c := range []struct {
+		src    string
+		expect time.Duration
+		ok     bool
+	}{
+		{"1w", 7 * day, true},
+		{"1w2d3h", 9*day + 3*time.Hour, true},
+		{"P1DT2H", day + 2*time.Hour, true},
+		{"PT15M", 15 * time.Minute, true},
+		{"P2W", 14 * day, true},
+		{"-PT1.5S", -1500 * time.Millisecond, true},
+		{"PT0,5S", 500 * time.Millisecond, true},
+		{"P0Y0M1D", day, true},
+		{"P1M", 0, false},
+		{"P1H", 0, false},
+		{"PT", 0, false},
+		{"P", 0, false},
+		{"1 day 2 hours", day + 2*time.Hour, true},
+		{"3 weeks, and 1 min", 21*day + time.Minute, true},
+		{"1h 30 minutes", 90 * time.Minute, true},
+		{"2Hours", 2 * time.Hour, true},
+		{"1 fortnight", 0, false},
+		{"1.5", 0, false},
+	} {
+		d, err := ParseDuration(c.src)
+		if c.ok && (err != nil || d != c.expect) {
+			t.Fatalf("%5d. expecting %v, but got %v, %v | src is %q", i, c.expect, d, err, c.src)
+		} else if !c.ok && err == nil {
+			t.Fatalf("%5d. expecting error, but got %v | src is %q", i, d, c.src)
+		}
+	}
+}
+
+func TestFormatISODuration(t *testing.T) {
+	for i, c := range []struct {
+		src    time.Duration
+		expect string
+	}{
+		{0, "PT0S"},
+		{26 * time.Hour, "P1DT2H"},
+		{15 * time.Minute, "PT15M"},
+		{48 * time.Hour, "P2D"},
+		{-1500 * time.Millisecond, "-PT1.5S"},
+		{time.Hour + 2*time.Second + 5*time.Millisecond, "PT1H2.005S"},
+		{time.Nanosecond, "PT0.000000001S"},
+	} {
+		s := FormatISODuration(c.src)
+		if s != c.expect {
+			t.Fatalf("%5d. expecting %q, but got %q", i, c.expect, s)
+		}
+		if d, err := ParseDuration(s); err != nil || d != c.src {
+			t.Fatalf("%5d. round-trip %q failed, got %v, %v", i, s, d, err)
+		}
+	}
+}
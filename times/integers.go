@@ -22,8 +22,8 @@ func SmartParseUint(s string) (ret uint64, err error) {
 	return
 }
 
-// MstSmartParseUint is a helper
-func MstSmartParseUint(s string) (ret uint64) {
+// MustSmartParseUint is a helper
+func MustSmartParseUint(s string) (ret uint64) {
 	ret, _ = strconv.ParseUint(s, 0, 64)
 	return
 }
@@ -8,7 +8,7 @@ func TestSmartParseInt(t *testing.T) {
 	_, _ = SmartParseInt("1")
 	_ = MustSmartParseInt("1")
 	_, _ = SmartParseUint("1")
-	_ = MstSmartParseUint("1")
+	_ = MustSmartParseUint("1")
 	_, _ = ParseFloat(".1")
 	_ = MustParseFloat(".1")
 	_, _ = ParseComplex(".1")
@@ -0,0 +1,170 @@
+package times
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseISODuration parses an ISO 8601 duration, such as "P1DT2H",
+// "PT15M", "P2W" or "-PT1.5S". A day is 24 hours and a week is 7 days.
+//
+// The years and months are rejected since they have no fixed length,
+// except that they are zero, such as "P0Y0M1D".
+func ParseISODuration(s string) (time.Duration, error) { //nolint:revive
+	orig := s
+	neg := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:] //nolint:revive
+	}
+	if len(s) < 2 || s[0] != 'P' { //nolint:gomnd //"P" and one part at least
+		return 0, errors.New("time: invalid ISO 8601 duration " + quote(orig))
+	}
+	s = s[1:] //nolint:revive
+
+	var d float64
+	inTime := false
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, errors.New("time: invalid ISO 8601 duration " + quote(orig))
+			}
+			inTime, s = true, s[1:] //nolint:revive
+			continue
+		}
+
+		i := strings.IndexFunc(s, func(r rune) bool { return r != '.' && r != ',' && (r < '0' || r > '9') })
+		if i <= 0 { // no number, or no designator
+			return 0, errors.New("time: invalid ISO 8601 duration " + quote(orig))
+		}
+		v, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, errors.New("time: invalid ISO 8601 duration " + quote(orig))
+		}
+
+		var unit time.Duration
+		switch c := s[i]; {
+		case !inTime && (c == 'Y' || c == 'M'):
+			if v != 0 {
+				return 0, errors.New("time: ambiguous years or months in ISO 8601 duration " + quote(orig))
+			}
+		case !inTime && c == 'W':
+			unit = 7 * 24 * time.Hour //nolint:gomnd //a week
+		case !inTime && c == 'D':
+			unit = 24 * time.Hour //nolint:gomnd //a day
+		case inTime && c == 'H':
+			unit = time.Hour
+		case inTime && c == 'M':
+			unit = time.Minute
+		case inTime && c == 'S':
+			unit = time.Second
+		default:
+			return 0, errors.New("time: unknown unit " + quote(s[i:i+1]) + " in ISO 8601 duration " + quote(orig))
+		}
+		d += v * float64(unit)
+		s = s[i+1:] //nolint:revive
+	}
+
+	if d = math.Round(d); d >= 1<<63 {
+		return 0, errors.New("time: invalid ISO 8601 duration " + quote(orig))
+	}
+	if neg {
+		return -time.Duration(d), nil
+	}
+	return time.Duration(d), nil
+}
+
+// FormatISODuration formats a duration in ISO 8601, such as "P1DT2H",
+// "PT15M" and "PT1.5S". It can be parsed by ParseISODuration and
+// ParseDuration.
+//
+// The days part is used for every 24 hours, the weeks, months and
+// years parts are never used.
+func FormatISODuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var sb strings.Builder
+	u := uint64(d)
+	if d < 0 {
+		sb.WriteByte('-')
+		u = -u
+	}
+	sb.WriteByte('P')
+
+	day := uint64(24 * time.Hour) //nolint:gomnd //a day
+	if days := u / day; days > 0 {
+		sb.WriteString(strconv.FormatUint(days, 10))
+		sb.WriteByte('D')
+	}
+	if u %= day; u == 0 {
+		return sb.String()
+	}
+
+	sb.WriteByte('T')
+	if h := u / uint64(time.Hour); h > 0 {
+		sb.WriteString(strconv.FormatUint(h, 10))
+		sb.WriteByte('H')
+	}
+	if m := u % uint64(time.Hour) / uint64(time.Minute); m > 0 {
+		sb.WriteString(strconv.FormatUint(m, 10))
+		sb.WriteByte('M')
+	}
+	if ns := u % uint64(time.Minute); ns > 0 {
+		sb.WriteString(strconv.FormatUint(ns/uint64(time.Second), 10))
+		if frac := ns % uint64(time.Second); frac > 0 {
+			f := strconv.FormatUint(frac+uint64(time.Second), 10) // "1xxxxxxxxx", keeps the leading zeros
+			sb.WriteByte('.')
+			sb.WriteString(strings.TrimRight(f[1:], "0"))
+		}
+		sb.WriteByte('S')
+	}
+	return sb.String()
+}
+
+// normalizeHumanDuration rewrites a human-written duration to the Go
+// style, such as "1 day 2 hours" to "1d2h", "3 weeks, and 1 min" to
+// "3w1m".
+func normalizeHumanDuration(s string) (norm string, ok bool) {
+	var sb strings.Builder
+	fields := strings.Fields(strings.ReplaceAll(strings.ToLower(s), ",", " "))
+	for _, f := range fields {
+		if f == "and" {
+			continue
+		}
+		j := strings.LastIndexFunc(f, func(r rune) bool { return r == '.' || '0' <= r && r <= '9' })
+		num, word := f[:j+1], f[j+1:]
+		if word == "" {
+			if j < 0 {
+				return // an empty field
+			}
+			sb.WriteString(num) // the unit is in next field
+			continue
+		}
+		if unit, found := humanUnits[word]; found {
+			sb.WriteString(num)
+			sb.WriteString(unit)
+		} else if _, found = unitMap[word]; found {
+			sb.WriteString(f)
+		} else {
+			return
+		}
+	}
+	return sb.String(), sb.Len() > 0
+}
+
+//nolint:gochecknoglobals //i know that
+var humanUnits = map[string]string{
+	"nanosecond": "ns", "nanoseconds": "ns", "nsec": "ns", "nsecs": "ns",
+	"microsecond": "us", "microseconds": "us", "usec": "us", "usecs": "us",
+	"millisecond": "ms", "milliseconds": "ms", "msec": "ms", "msecs": "ms",
+	"second": "s", "seconds": "s", "sec": "s", "secs": "s",
+	"minute": "m", "minutes": "m", "min": "m", "mins": "m",
+	"hour": "h", "hours": "h", "hr": "h", "hrs": "h",
+	"day": "d", "days": "d",
+	"week": "w", "weeks": "w", "wk": "w", "wks": "w",
+}
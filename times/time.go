@@ -1,3 +1,9 @@
+// Package times provides the smart parsing and formatting of time.Time
+// and time.Duration, which are used by the evendeep converters.
+//
+// A time layout registered by AddKnownTimeFormats takes effect on every
+// string to time.Time conversion of evendeep, such as evendeep.Cvt and
+// the copiers.
 package times
 
 import (
@@ -58,78 +64,100 @@ func MustSmartParseTimePtr(str string) (tm *time.Time) {
 // differ by the actual zone offset. To avoid such problems, prefer time layouts
 // that use a numeric zone offset, or use ParseInLocation.
 func SmartParseTime(str string) (tm time.Time, err error) {
-	for _, layout := range onceInitTimeFormats() {
-		if tm, err = time.Parse(layout, str); err == nil {
+	return SmartParseTimeInLocation(str, time.UTC)
+}
+
+// SmartParseTimeInLocation is like SmartParseTime but parses the
+// time string without zone in the given location, see also
+// time.ParseInLocation.
+func SmartParseTimeInLocation(str string, loc *time.Location) (tm time.Time, err error) {
+	knownFormatsLock.RLock()
+	defer knownFormatsLock.RUnlock()
+	for _, layout := range knownDateTimeFormats {
+		if tm, err = time.ParseInLocation(layout, str, loc); err == nil {
 			break
 		}
 	}
 	return
 }
 
-var (
-	knownDateTimeFormats []string
-	onceFormats          sync.Once
-)
-
-func onceInitTimeFormats() []string {
-	onceFormats.Do(func() {
-		knownDateTimeFormats = []string{
-			"2006-01-02 15:04:05.999999999 -0700",
-			"2006-01-02 15:04:05.999999999Z07:00",
-			"2006-01-02 15:04:05.999999999",
-			"2006-01-02 15:04:05.999",
-			"2006-01-02 15:04:05",
-			"2006-01-02",
-			"2006/01/02",
-			"01/02/2006",
-			"01-02",
-
-			"2006-1-2 15:4:5.999999999 -0700",
-			"2006-1-2 15:4:5.999999999Z07:00",
-			"2006-1-2 15:4:5.999999999",
-			"2006-1-2 15:4:5.999",
-			"2006-1-2 15:4:5",
-			"2006-1-2",
-			"2006/1/2",
-			"1/2/2006",
-			"1-2",
-
-			"15:04:05.999999999",
-			"15:04.999999999",
-			"15:04:05.999",
-			"15:04.999",
-			"15:04:05",
-			"15:04",
-
-			"15:4:5.999999999",
-			"15:4.999999999",
-			"15:4:5.999",
-			"15:4.999",
-			"15:4:5",
-			"15:4",
-
-			time.RFC3339,
-			time.RFC3339Nano,
-			time.RFC1123Z,
-			time.RFC1123,
-			time.RFC850,
-			time.RFC822Z,
-			time.RFC822,
-			time.RubyDate,
-			time.UnixDate,
-			time.ANSIC,
-		}
-	})
-	return knownDateTimeFormats
+// KnownTimeFormats returns a copy of the time layouts list which is
+// tried in order by SmartParseTime.
+func KnownTimeFormats() []string {
+	knownFormatsLock.RLock()
+	defer knownFormatsLock.RUnlock()
+	return append([]string(nil), knownDateTimeFormats...)
 }
 
 // AddKnownTimeFormats appends more time layouts to the trying list
 // used by SmartParseTime.
 func AddKnownTimeFormats(format ...string) {
-	onceInitTimeFormats()
+	knownFormatsLock.Lock()
+	defer knownFormatsLock.Unlock()
 	knownDateTimeFormats = append(knownDateTimeFormats, format...)
 }
 
+//nolint:gochecknoglobals //i know that
+var (
+	knownDateTimeFormats = []string{
+		"2006-01-02 15:04:05.999999999 -0700",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02 15:04:05.999",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"2006/01/02",
+		"01/02/2006",
+		"01-02",
+
+		"2006-1-2 15:4:5.999999999 -0700",
+		"2006-1-2 15:4:5.999999999Z07:00",
+		"2006-1-2 15:4:5.999999999",
+		"2006-1-2 15:4:5.999",
+		"2006-1-2 15:4:5",
+		"2006-1-2",
+		"2006/1/2",
+		"1/2/2006",
+		"1-2",
+
+		"15:04:05.999999999",
+		"15:04.999999999",
+		"15:04:05.999",
+		"15:04.999",
+		"15:04:05",
+		"15:04",
+
+		"15:4:5.999999999",
+		"15:4.999999999",
+		"15:4:5.999",
+		"15:4.999",
+		"15:4:5",
+		"15:4",
+
+		time.RFC3339,
+		time.RFC3339Nano,
+		time.RFC1123Z,
+		time.RFC1123,
+		time.RFC850,
+		time.RFC822Z,
+		time.RFC822,
+		time.RubyDate,
+		time.UnixDate,
+		time.ANSIC,
+		time.Kitchen,
+		time.Stamp,
+		time.StampMilli,
+		time.StampMicro,
+		time.StampNano,
+
+		"2006-01-02 15:04",
+		"01/02/2006 15:04:05.999999999",
+		"01/02/2006 15:04:05",
+		"01/02/2006 15:04",
+	}
+	knownFormatsLock sync.RWMutex
+)
+
 // // RoundedSince strips small ticks from a time.Time value.
 // //
 // // For example,
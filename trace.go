@@ -0,0 +1,387 @@
+package evendeep
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/hedzr/evendeep/flags"
+	"github.com/hedzr/evendeep/flags/cms"
+	"github.com/hedzr/evendeep/ref"
+)
+
+// Explain performs a deep copy from fromObjOrPtr to toObjPtr like
+// New(opts...).CopyTo does, and returns a Trace which records the
+// decisions made for each target path: which source field was
+// matched and why, which ValueConverter or ValueCopier was picked,
+// which strategies were applied and what the result value is.
+//
+// The trace can be rendered as text by Trace.String(), or as JSON
+// by json.Marshal(trace).
+//
+// For instance:
+//
+//	trace := evendeep.Explain(src, &tgt, evendeep.WithByNameStrategyOpt)
+//	fmt.Println(trace)
+func Explain(fromObjOrPtr, toObjPtr interface{}, opts ...Opt) (trace *Trace) { //nolint:revive
+	c := newDeepCopier()
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	t := newTracer(fromObjOrPtr, c.flags)
+	c.tracer = t
+	err := c.CopyTo(fromObjOrPtr, toObjPtr)
+	c.tracer = nil
+
+	t.finish()
+	trace = &Trace{Root: t.root, Err: err}
+	return
+}
+
+// TraceAction tells what has been done on a target path.
+type TraceAction string
+
+const (
+	// TraceCopied means the source value was copied to target.
+	TraceCopied TraceAction = "copied"
+	// TraceConverted means the source value was transformed by
+	// a ValueConverter or a ValueCopier.
+	TraceConverted TraceAction = "converted"
+	// TraceSkipped means the target was left untouched.
+	TraceSkipped TraceAction = "skipped"
+	// TraceCleared means the target was set to zero value.
+	TraceCleared TraceAction = "cleared"
+)
+
+// Trace is the decision tree returned by Explain.
+type Trace struct {
+	Root *TraceNode `json:"root"`
+	Err  error      `json:"-"`
+}
+
+// TraceNode records the decisions made for one target path.
+type TraceNode struct {
+	Path      string       `json:"path"`                // target path, such as "Inner.Name"
+	Source    string       `json:"source,omitempty"`    // source field matched
+	Match     string       `json:"match,omitempty"`     // why the source was matched: byordinal, byname, tag rule, ...
+	Action    TraceAction  `json:"action,omitempty"`    // what has been done
+	Reason    string       `json:"reason,omitempty"`    // why it was skipped or cleared
+	Converter string       `json:"converter,omitempty"` // the ValueConverter picked by findConverters
+	Copier    string       `json:"copier,omitempty"`    // the ValueCopier picked by findCopiers
+	Flags     string       `json:"flags,omitempty"`     // the strategies applied
+	Value     string       `json:"value,omitempty"`     // the result value
+	Error     string       `json:"error,omitempty"`     // the error occurred
+	Children  []*TraceNode `json:"children,omitempty"`
+
+	depth int
+	dst   *reflect.Value
+}
+
+// MarshalJSON renders the trace as JSON, the error is rendered
+// as string.
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	var errStr string
+	if t.Err != nil {
+		errStr = t.Err.Error()
+	}
+	return json.Marshal(struct {
+		Root  *TraceNode `json:"root"`
+		Error string     `json:"error,omitempty"`
+	}{t.Root, errStr})
+}
+
+// String renders the trace as an indented text tree.
+func (t *Trace) String() string {
+	var sb strings.Builder
+	if t.Root != nil {
+		t.Root.write(&sb, 0, "")
+	}
+	if t.Err != nil {
+		_, _ = sb.WriteString("error: ")
+		_, _ = sb.WriteString(t.Err.Error())
+		_, _ = sb.WriteRune('\n')
+	}
+	return sb.String()
+}
+
+func (n *TraceNode) write(sb *strings.Builder, level int, parentFlags string) {
+	_, _ = sb.WriteString(strings.Repeat("  ", level))
+	if n.Path == "" {
+		_, _ = sb.WriteString("(root)")
+	} else {
+		_, _ = sb.WriteString(n.Path)
+	}
+	if n.Source != "" {
+		_, _ = sb.WriteString(" <- ")
+		_, _ = sb.WriteString(n.Source)
+	}
+	if n.Match != "" {
+		_, _ = sb.WriteString(" [" + n.Match + "]")
+	}
+	if n.Action != "" {
+		_, _ = sb.WriteString(" " + string(n.Action))
+	}
+	if n.Reason != "" {
+		_, _ = sb.WriteString(" (" + n.Reason + ")")
+	}
+	if n.Converter != "" {
+		_, _ = sb.WriteString(" via " + n.Converter)
+	}
+	if n.Copier != "" {
+		_, _ = sb.WriteString(" via " + n.Copier)
+	}
+	if n.Flags != "" && n.Flags != parentFlags {
+		_, _ = sb.WriteString(" " + n.Flags) // print the strategies only if they changed
+	}
+	if n.Value != "" {
+		_, _ = sb.WriteString(" = " + n.Value)
+	}
+	if n.Error != "" {
+		_, _ = sb.WriteString(" error: " + n.Error)
+	}
+	_, _ = sb.WriteRune('\n')
+	pf := parentFlags
+	if n.Flags != "" {
+		pf = n.Flags
+	}
+	for _, child := range n.Children {
+		child.write(sb, level+1, pf)
+	}
+}
+
+// tracer collects TraceNode's while a cpController is copying.
+//
+// All methods are nil-safe so the callers in ftor.go need not
+// check whether tracing is enabled.
+type tracer struct {
+	root  *TraceNode
+	stack []*TraceNode
+}
+
+func newTracer(fromObjOrPtr interface{}, f flags.Flags) *tracer { //nolint:revive
+	root := &TraceNode{Action: TraceCopied, Flags: f.StringEx()}
+	if fromObjOrPtr != nil {
+		root.Source = reflect.TypeOf(fromObjOrPtr).String()
+	}
+	return &tracer{root: root}
+}
+
+// enter starts a new node for the target field pointed by
+// params.accessor.
+func (t *tracer) enter(params *Params, name, source, match string) (node *TraceNode) {
+	if node = t.add(params, name, source, match, TraceCopied, ""); node != nil {
+		node.Flags = traceFlags(params)
+		if params.accessor != nil && params.accessor.IsStruct() {
+			node.dst = params.accessor.FieldValue() // addressable, read it at finish
+		}
+	}
+	return
+}
+
+// add starts a new node. The nesting level is decided by
+// params.depth(), so the siblings and the nested children of
+// the previous field are closed automatically.
+func (t *tracer) add(params *Params, name, source, match string, action TraceAction, reason string) (node *TraceNode) { //nolint:lll
+	if t == nil {
+		return
+	}
+
+	depth := params.depth()
+	for len(t.stack) > 0 && t.stack[len(t.stack)-1].depth >= depth {
+		t.stack = t.stack[:len(t.stack)-1]
+	}
+	parent := t.root
+	if len(t.stack) > 0 {
+		parent = t.stack[len(t.stack)-1]
+	}
+
+	node = &TraceNode{
+		Path:   name,
+		Source: source,
+		Match:  match,
+		Action: action,
+		Reason: reason,
+		depth:  depth,
+	}
+	if parent.Path != "" {
+		node.Path = parent.Path + "." + name
+	}
+
+	// a target field might be visited more than once, for example,
+	// cms.ByName iterates the matched source fields and then all
+	// the target fields. Keep one node per target path, and don't
+	// let a skipping override a real decision.
+	attached := false
+	for i, sib := range parent.Children {
+		if sib.Path == node.Path {
+			if action != TraceSkipped || sib.Action == TraceSkipped {
+				parent.Children[i] = node
+			}
+			attached = true
+			break
+		}
+	}
+	if !attached {
+		parent.Children = append(parent.Children, node)
+	}
+	t.stack = append(t.stack, node)
+	return
+}
+
+// tracePath returns the target path for a source record, the
+// parents of a flattened nested struct field are kept.
+func tracePath(sourceField *tableRecT, name string) string {
+	if sourceField == nil || len(sourceField.names) < 2 {
+		return name
+	}
+	var sb strings.Builder
+	for i := len(sourceField.names) - 1; i > 0; i-- {
+		_, _ = sb.WriteString(sourceField.names[i])
+		_, _ = sb.WriteRune('.')
+	}
+	_, _ = sb.WriteString(name)
+	return sb.String()
+}
+
+func (t *tracer) current() *TraceNode {
+	if t == nil {
+		return nil
+	}
+	if len(t.stack) > 0 {
+		return t.stack[len(t.stack)-1]
+	}
+	return t.root
+}
+
+// skip marks the current node as skipped.
+func (t *tracer) skip(reason string) {
+	if n := t.current(); n != nil {
+		n.Action, n.Reason, n.dst = TraceSkipped, reason, nil
+	}
+}
+
+// note records a reason for the current node without changing
+// its action.
+func (t *tracer) note(reason string) {
+	if n := t.current(); n != nil {
+		n.Reason = reason
+	}
+}
+
+// match overrides the matching reason of the current node.
+func (t *tracer) match(source, match string) {
+	if n := t.current(); n != nil {
+		n.Source, n.Match = source, match
+	}
+}
+
+// fail records an error for the current node.
+func (t *tracer) fail(err error) {
+	if n := t.current(); n != nil && err != nil {
+		n.Error = err.Error()
+	}
+}
+
+// clear marks the current node as cleared.
+func (t *tracer) clear(reason string) {
+	if n := t.current(); n != nil {
+		n.Action, n.Reason = TraceCleared, reason
+	}
+}
+
+// converter records the ValueConverter picked for the current node.
+// Only the first one is kept, the nested ones are applied on the
+// elements of the current field.
+func (t *tracer) converter(cvt ValueConverter) {
+	if n := t.current(); n != nil && n.Converter == "" && n.Copier == "" {
+		n.Action, n.Converter = TraceConverted, reflect.TypeOf(cvt).String()
+	}
+}
+
+// copier records the ValueCopier picked for the current node.
+func (t *tracer) copier(cpr ValueCopier) {
+	if n := t.current(); n != nil && n.Converter == "" && n.Copier == "" {
+		n.Action, n.Copier = TraceConverted, reflect.TypeOf(cpr).String()
+	}
+}
+
+// result records the value which was set into a non-addressable
+// target, such as a map item.
+func (t *tracer) result(v reflect.Value) {
+	if n := t.current(); n != nil && n.dst == nil && v.IsValid() {
+		n.dst = &v
+	}
+}
+
+// finish fills the result values after copying completed.
+func (t *tracer) finish() {
+	if t == nil {
+		return
+	}
+	t.root.finish()
+	t.stack = nil
+}
+
+func (n *TraceNode) finish() {
+	if n.dst != nil && n.dst.IsValid() && n.Action != TraceSkipped {
+		n.Value = ref.Valfmt(n.dst)
+	}
+	n.dst = nil
+	for _, child := range n.Children {
+		child.finish()
+	}
+}
+
+// tracer returns the tracer of the controller, or nil.
+func (params *Params) tracer() *tracer {
+	if params == nil || params.controller == nil {
+		return nil
+	}
+	return params.controller.tracer
+}
+
+// traceFlags returns the strategies of the controller merged with
+// the ones declared in the struct tag of the current field.
+func traceFlags(params *Params) string {
+	if params == nil || params.controller == nil {
+		return ""
+	}
+	f := flags.New()
+	for k, v := range params.controller.flags {
+		if v {
+			f.WithFlags(k)
+		}
+	}
+	for k, v := range params.flags {
+		if v {
+			f.WithFlags(k)
+		}
+	}
+	if params.accessor != nil {
+		if sf := params.accessor.StructField(); sf != nil {
+			tagName := params.controller.tagKeyName
+			if tagName == "" {
+				tagName = flags.CopyTagName
+			}
+			for i, word := range strings.Split(sf.Tag.Get(tagName), ",") {
+				if i == 0 {
+					continue // the name convert rule
+				}
+				if ftf := cms.Default.Parse(word); ftf != cms.InvalidStrategy {
+					f.WithFlags(ftf)
+				}
+			}
+		}
+	}
+	return f.StringEx()
+}
+
+// traceMatchRule returns the name convert rule of a struct field tag
+// if it's valid, or def.
+func traceMatchRule(flagsInTag *fieldTags, def string) string {
+	if flagsInTag != nil && flagsInTag.nameConvertRule.Valid() {
+		return "tag:" + string(flagsInTag.nameConvertRule)
+	}
+	return def
+}
@@ -0,0 +1,111 @@
+package evendeep_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hedzr/evendeep"
+)
+
+func TestExplain(t *testing.T) {
+	type Inner struct {
+		N int
+	}
+	type A struct {
+		Name   string
+		Age    int
+		Secret string `copy:"-"`
+		Nick   string `copy:"Alias"`
+		In     Inner
+	}
+	type B struct {
+		Name   string
+		Age    string
+		Secret string
+		Alias  string
+		In     Inner
+	}
+
+	src := &A{Name: "x", Age: 3, Secret: "s", Nick: "nick", In: Inner{N: 1}}
+	var tgt B
+	trace := evendeep.Explain(src, &tgt, evendeep.WithSyncAdvancingOpt)
+	if trace.Err != nil {
+		t.Fatalf("err: %v", trace.Err)
+	}
+	t.Logf("\n%v", trace)
+
+	nodes := make(map[string]*evendeep.TraceNode)
+	var walk func(n *evendeep.TraceNode)
+	walk = func(n *evendeep.TraceNode) {
+		nodes[n.Path] = n
+		for _, c := range n.Children {
+			walk(c)
+		}
+	}
+	walk(trace.Root)
+
+	if n := nodes["Name"]; n == nil || n.Match != "byordinal" || n.Value != "x" {
+		t.Fatalf("bad trace for Name: %+v", n)
+	}
+	if n := nodes["Age"]; n == nil || n.Action != evendeep.TraceConverted || n.Value != "3" {
+		t.Fatalf("bad trace for Age: %+v", n)
+	}
+	if n := nodes["Secret"]; n == nil || n.Action != evendeep.TraceSkipped {
+		t.Fatalf("bad trace for Secret: %+v", n)
+	}
+	if n := nodes["Alias"]; n == nil || !strings.HasPrefix(n.Match, "tag:") || n.Source != "Nick" {
+		t.Fatalf("bad trace for Alias: %+v", n)
+	}
+	if tgt.Age != "3" || tgt.Secret != "" || tgt.Alias != "nick" || tgt.In.N != 1 {
+		t.Fatalf("bad copy result: %+v", tgt)
+	}
+
+	b, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	t.Logf("%s", b)
+	if !strings.Contains(string(b), `"path":"Alias"`) {
+		t.Fatalf("bad json: %s", b)
+	}
+}
+
+func TestExplainByName(t *testing.T) {
+	type A struct {
+		Name string
+		Age  int
+	}
+	type B struct {
+		Age   int
+		Name  string
+		Extra string
+	}
+
+	src := &A{Name: "x", Age: 3}
+	tgt := B{Extra: "keep"}
+	trace := evendeep.Explain(src, &tgt, evendeep.WithByNameStrategyOpt)
+	if trace.Err != nil {
+		t.Fatalf("err: %v", trace.Err)
+	}
+	t.Logf("\n%v", trace)
+
+	if len(trace.Root.Children) != 3 {
+		t.Fatalf("expecting 3 nodes, but got %d", len(trace.Root.Children))
+	}
+	for _, n := range trace.Root.Children {
+		switch n.Path {
+		case "Age", "Name":
+			if n.Match != "byname" || n.Action != evendeep.TraceCopied {
+				t.Fatalf("bad trace for %q: %+v", n.Path, n)
+			}
+		case "Extra":
+			if n.Action != evendeep.TraceSkipped {
+				t.Fatalf("bad trace for %q: %+v", n.Path, n)
+			}
+		}
+	}
+	if tgt.Name != "x" || tgt.Age != 3 || tgt.Extra != "keep" {
+		t.Fatalf("bad copy result: %+v", tgt)
+	}
+}
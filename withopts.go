@@ -2,6 +2,8 @@ package evendeep
 
 import (
 	"encoding/json"
+	"reflect"
+	"time"
 
 	"github.com/hedzr/evendeep/flags"
 	"github.com/hedzr/evendeep/flags/cms"
@@ -26,6 +28,15 @@ func WithValueCopiers(cvt ...ValueCopier) Opt {
 	}
 }
 
+// WithCollectionAdapters gives a set of CollectionAdapter.
+// The adapters are tried in order to fill a user collection
+// target (such as a set, an ordered map, or a linked list).
+func WithCollectionAdapters(adapters ...CollectionAdapter) Opt {
+	return func(c *cpController) {
+		c.collectionAdapters = append(c.collectionAdapters, adapters...)
+	}
+}
+
 // WithTryApplyConverterAtFirst specifies which is first when
 // trying/applying ValueConverters and ValueCopiers.
 func WithTryApplyConverterAtFirst(b bool) Opt {
@@ -69,6 +80,35 @@ func WithSourceValueExtractor(e SourceValueExtractor) Opt {
 	}
 }
 
+// WithSourcePathExtractor specifies a source field value extractor
+// which is invoked with the path of each target field, at every
+// nesting level. The source object is ignored.
+//
+// It can work together with WithTargetValueSetter.
+//
+// For instance:
+//
+//	type Config struct {
+//	    DB struct {
+//	        Host string `env:"DB_HOST"`
+//	        Port int    `env:"DB_PORT"`
+//	    }
+//	}
+//
+//	var cfg Config
+//	err := evendeep.New().CopyTo(os.Environ(), &cfg,
+//	  evendeep.WithSourcePathExtractor(func(path []string, f reflect.StructField) (any, bool) {
+//	    if key := f.Tag.Get("env"); key != "" {
+//	      return os.LookupEnv(key)
+//	    }
+//	    return nil, false
+//	  }))
+func WithSourcePathExtractor(e SourcePathExtractor) Opt {
+	return func(c *cpController) {
+		c.pathExtractor = e
+	}
+}
+
 // WithTargetValueSetter _
 //
 // In the TargetValueSetter you could return evendeep.ErrShouldFallback to
@@ -311,6 +351,52 @@ func WithPassSourceToTargetFunction(b bool) Opt {
 // WithPassSourceToTargetFunctionOpt is shortcut of WithPassSourceToTargetFunction.
 var WithPassSourceToTargetFunctionOpt = WithPassSourceToTargetFunction(true) //nolint:gochecknoglobals //i know that
 
+// WithFuncAdapters copies a function into a target function of the
+// different type by wrapping it into an adapter, which converts the
+// arguments and the results through the converters. For instance, a
+// `func(int) string` field can be copied into a
+// `func(int64) fmt.Stringer` field.
+//
+// The `adapt` tag enables it for a struct field only:
+//
+//	type HooksV2 struct {
+//	    OnSave func(id int64) error `copy:",adapt"`
+//	}
+//
+// If the target function has no trailing error result to receive the
+// conversion errors, the values must be converted safely, or else
+// ErrCannotAdaptFunc is reported.
+//
+// Default is false, the different function types cannot be copied.
+func WithFuncAdapters(b bool) Opt {
+	return func(c *cpController) {
+		c.funcAdapters = b
+	}
+}
+
+// WithFuncAdaptersOpt is shortcut of WithFuncAdapters.
+var WithFuncAdaptersOpt = WithFuncAdapters(true) //nolint:gochecknoglobals //i know that
+
+// WithAccessors copies the properties exposed by methods besides the
+// struct fields: a getter `GetName()` or `Name()` of the source is
+// read as the property Name, and a setter `SetName(v)` of the target
+// is called to write it. It's useful to copy a protobuf-generated
+// message into a domain struct, or the reverse.
+//
+// The getters and setters are matched by name, and the values are
+// converted by the converters as the fields. A getter or setter may
+// return an error as its last result, which breaks the copying.
+//
+// Default is false.
+func WithAccessors(b bool) Opt {
+	return func(c *cpController) {
+		c.accessors = b
+	}
+}
+
+// WithAccessorsOpt is shortcut of WithAccessors.
+var WithAccessorsOpt = WithAccessors(true) //nolint:gochecknoglobals //i know that
+
 // WithSyncAdvancing decides how to advance to next field especially
 // a source field had been ignored.
 // By default, (false), the target field won't be advanced while the
@@ -327,6 +413,269 @@ func WithSyncAdvancing(syncAdvancing bool) Opt {
 // WithSyncAdvancingOpt is synonym of WithAutoExpandForInnerStruct(true).
 var WithSyncAdvancingOpt = WithSyncAdvancing(true) //nolint:gochecknoglobals //i know that
 
+// WithPreserveAliasing decides how the shared references in source
+// are copied.
+//
+// When preserve is true, the references to a same object, map or
+// slice in source are mapped 1:1 to one new object in target. That
+// is, if two fields point to the same object in the source, the two
+// fields of the clone point to the same new object. The pointers to
+// struct are not expanded (see WithAutoExpandForInnerStruct) in this
+// mode since each of them should be mapped.
+//
+// When preserve is false, each reference is duplicated to a new
+// object. The pointer elements of a slice are cloned only in preserve
+// mode (or by MakeClone), else they're shared with the source as the
+// plain copying does.
+//
+// The cycles (such as parent<->child back-pointers, self-referencing
+// maps and slices) are reproduced in both modes, the back-reference
+// in target points to the object being copied rather than being cut.
+//
+// Default is true for MakeClone.
+func WithPreserveAliasing(preserve bool) Opt {
+	return func(c *cpController) {
+		c.preserveAliasing = preserve
+	}
+}
+
+// WithPreserveAliasingOpt is synonym of WithPreserveAliasing(true).
+var WithPreserveAliasingOpt = WithPreserveAliasing(true) //nolint:gochecknoglobals //i know that
+
+// WithStrictConversions reports a *ConversionError for a lossy
+// numeric conversion, instead of wrapping around as Go does:
+//
+//   - an overflow, such as int64(300) to int8 (ErrOverflow)
+//   - a float with fraction to an integer, such as 2.7 to int
+//     (ErrTruncated)
+//   - an integer which cannot be represented exactly by the target
+//     float, such as 1<<53+1 to float64 (ErrPrecisionLost)
+//   - a negative value to an unsigned integer (ErrNegativeToUnsigned)
+//   - a string which isn't a number, or has trailing garbage, such
+//     as "12abc" to int (ErrBadSyntax)
+//
+// It's same as WithStrategies(cms.Strict). A struct field can be
+// checked individually by tag `copy:",strict"`.
+func WithStrictConversions() Opt {
+	return func(c *cpController) {
+		WithStrategies(cms.Strict)(c)
+		delete(c.flags, cms.Saturate)
+	}
+}
+
+// WithNumberFormat selects a NumberFormat registered by name to parse
+// the human-written strings when they're copied to integer or float
+// targets, such as "1,234.56", "12%" or "1.5MiB". The builtin names
+// are "human", "eu", "bytesize" and "percent".
+//
+// A struct field can select its format by tag `copy:",parse=bytesize"`.
+// An unregistered name is reported as ErrUnknownName in copying.
+func WithNumberFormat(name string) Opt {
+	return func(c *cpController) {
+		c.numberFormat = name
+	}
+}
+
+// WithTimeLayouts specifies the layouts to parse a string to time.Time
+// in order, instead of the builtin layouts.
+//
+// A struct field can specify its own layout by tag
+// `copy:",timefmt=2006-01-02"`, which is used for both parsing and
+// formatting.
+func WithTimeLayouts(layouts ...string) Opt {
+	return func(c *cpController) {
+		c.timeLayouts = layouts
+	}
+}
+
+// WithTimeFormat specifies the layout to format a time.Time to string,
+// the default is time.RFC3339.
+func WithTimeFormat(layout string) Opt {
+	return func(c *cpController) {
+		c.timeFormat = layout
+	}
+}
+
+// WithTimeLocation specifies the location to parse a time string which
+// has no zone, instead of UTC. The times are converted to loc too
+// when they're formatted to string or made from an epoch number.
+func WithTimeLocation(loc *time.Location) Opt {
+	return func(c *cpController) {
+		c.timeLocation = loc
+	}
+}
+
+// WithEpochUnit specifies the unit of an integer epoch when it's
+// converted from/to time.Time, one of time.Second (default),
+// time.Millisecond, time.Microsecond and time.Nanosecond.
+//
+// A struct field can specify its unit by tag `copy:",epoch=ms"`, the
+// valid values are s, ms, us and ns, another one is reported as
+// ErrUnknownName in copying.
+func WithEpochUnit(unit time.Duration) Opt {
+	return func(c *cpController) {
+		c.epochUnit = unit
+	}
+}
+
+// WithDurationFormat specifies the format of a time.Duration converted
+// to string, one of DurationFormatGo (default, "26h0m0s"),
+// DurationFormatISO8601 ("P1DT2H") and DurationFormatSmart ("1d2h").
+// All of them can be parsed back to time.Duration.
+//
+// A struct field can specify its format by tag
+// `copy:",durfmt=iso8601"`. An unknown name yields ErrUnknownName in
+// copying.
+func WithDurationFormat(name string) Opt {
+	return func(c *cpController) {
+		c.durationFormat = name
+	}
+}
+
+// WithChanStrategy specifies how to copy a channel, one of ChanShare
+// (default, the target shares the source channel), ChanFresh (a new
+// empty channel of the same capacity) and ChanSnapshot (a new channel
+// with a copy of the buffered elements).
+//
+// A struct field can specify its strategy by tag
+// `copy:",chan=snapshot"`.
+//
+// A channel of the different element type can't be shared, it's
+// copied as ChanSnapshot for ChanShare. An unknown name is reported as
+// ErrUnknownName in copying.
+func WithChanStrategy(name string) Opt {
+	return func(c *cpController) {
+		c.chanStrategy = name
+	}
+}
+
+// WithLockReport specifies a func to report the source type which
+// holds any locks, with the paths of the lock fields (see
+// LockFields). It's invoked before copying.
+//
+// The locks are never copied as is: a mutex is reset to zero, an
+// atomic value is copied by Load and Store, and a sync.Map is
+// deep-copied. But a struct which holds locks is usually not designed
+// to be cloned, the report helps to find it out.
+func WithLockReport(report func(typ reflect.Type, paths []string)) Opt {
+	return func(c *cpController) {
+		c.lockReporter = report
+	}
+}
+
+// WithMapKeyTransform specifies a func to rename the string keys of
+// the target map in copying a map, such as strings.ToLower, or
+// KeysByNameConverter(nc).
+//
+// A struct field can specify a registered transform by tag
+// `copy:",keys=lower"`, see RegisterMapKeyTransform.
+//
+// The keys which collide after transformed are reported as
+// ErrKeyCollision, see WithKeyCollision.
+func WithMapKeyTransform(transform MapKeyTransform) Opt {
+	return func(c *cpController) {
+		c.keyTransform = transform
+	}
+}
+
+// WithKeyCollision specifies the policy for the source keys which are
+// mapped to the same target key in copying a map, one of
+// KeyCollisionError (default), KeyCollisionFirst and KeyCollisionLast.
+// The source keys are sorted to resolve them in a stable order.
+//
+// A struct field can specify its policy by tag `copy:",collide=last"`.
+func WithKeyCollision(policy string) Opt {
+	return func(c *cpController) {
+		c.keyCollision = policy
+	}
+}
+
+// WithMapMergePolicy specifies how to merge a key which exists in both
+// the source and target map, one of MapMergeDeep (default),
+// MapMergeOverride, MapMergeKeep, or the name of a registered
+// MapMergeResolver. It works for the nested maps recursively. An
+// unregistered name is reported as ErrUnknownName in merging.
+//
+// A struct field can specify its policy by tag
+// `copy:",mapmerge=override"`.
+func WithMapMergePolicy(policy string) Opt {
+	return func(c *cpController) {
+		c.mapMergePolicy, c.mapMergeResolver = policy, nil
+	}
+}
+
+// WithMapMergeResolver specifies a func to resolve the value of a key
+// which exists in both the source and target map.
+func WithMapMergeResolver(resolver MapMergeResolver) Opt {
+	return func(c *cpController) {
+		c.mapMergePolicy, c.mapMergeResolver = "", resolver
+	}
+}
+
+// WithSliceDedupKey specifies a func to get the key of slice elements
+// for cms.SliceDedup, such as the ID of a struct element. The elements
+// with the same key are deduplicated, and a source element replaces
+// the target one in place.
+//
+// A struct field can specify the key field of its elements by tag
+// `copy:",slicededup,dedupkey=ID"`, an element without such field
+// (or map key) is reported as ErrNoDedupKey.
+func WithSliceDedupKey(key func(elem any) any) Opt {
+	return func(c *cpController) {
+		c.sliceDedupKey = key
+	}
+}
+
+// WithLengthPolicy specifies how to copy an array or slice into an
+// array, or an array into a slice, if their lengths are different, one
+// of LenTruncate, LenPad, LenStrict and LenResize. The defaults are
+// LenTruncate for an array to array, LenPad for a slice to array, and
+// LenResize for an array to slice.
+//
+// A struct field can specify its policy by tag `copy:",len=strict"`.
+// An unknown policy is reported as ErrUnknownName in copying.
+func WithLengthPolicy(policy string) Opt {
+	return func(c *cpController) {
+		c.lengthPolicy = policy
+	}
+}
+
+// WithShareBytesAbove shares a []byte (or a named byte slice type)
+// whose length is not smaller than size with the target, rather than
+// copying it. It cuts the allocations heavily in cloning the large
+// read-mostly state, but the target must not mutate the shared bytes.
+//
+// See also RegisterImmutableType and the `share` tag.
+func WithShareBytesAbove(size int) Opt {
+	return func(c *cpController) {
+		c.shareBytesAbove = size
+	}
+}
+
+// WithZeroAsNull converts a zero value (such as "" or 0) to an
+// invalid sql.Null* target (Valid=false). By default, a plain value is
+// always converted to a valid one, and only a nil pointer is NULL.
+func WithZeroAsNull() Opt {
+	return func(c *cpController) {
+		c.zeroAsNull = true
+	}
+}
+
+// WithSaturatingConversions clamps a numeric value to the range of
+// its target type instead of wrapping around, such as int64(300) to
+// int8 is 127, -1 to uint is 0. A float with fraction to an integer
+// is truncated toward zero, so is a numeric string such as "2.7", and
+// a NaN becomes 0.
+//
+// It's same as WithStrategies(cms.Saturate). A struct field can be
+// clamped individually by tag `copy:",saturate"`.
+func WithSaturatingConversions() Opt {
+	return func(c *cpController) {
+		WithStrategies(cms.Saturate)(c)
+		delete(c.flags, cms.Strict)
+	}
+}
+
 // WithWipeTargetSliceFirst enables the option which assumes the target
 // Slice or Map will be wipe out at first before copying/merging from
 // source field.